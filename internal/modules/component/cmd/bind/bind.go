@@ -7,13 +7,23 @@ import (
 )
 
 func Init() *cobra.Command {
-	return &cobra.Command{
+	var componentName, metricName string
+	var plan, refreshFacts bool
+
+	cmd := &cobra.Command{
 		Use:   "bind",
 		Short: "Bind components to metrics",
 		Run: func(cmd *cobra.Command, args []string) {
 			handler := initializeHandler()
 			ctx := commandcontext.Init()
-			handler.Bind(ctx, yaml.StateLocation)
+			handler.Bind(ctx, yaml.StateLocation, componentName, metricName, plan, refreshFacts)
 		},
 	}
+
+	cmd.Flags().StringVarP(&componentName, "component", "c", "", "Name of the component to bind")
+	cmd.Flags().StringVarP(&metricName, "metric", "m", "", "Name of the metric to bind")
+	cmd.Flags().BoolVarP(&plan, "plan", "p", false, "Print the metric sources to create and remove without applying them")
+	cmd.Flags().BoolVar(&refreshFacts, "refresh-facts", false, "Bind again the facts of the stale metric sources, without creating or removing metric sources")
+
+	return cmd
 }
@@ -6,11 +6,14 @@ import (
 	"github.com/google/wire"
 	"github.com/motain/of-catalog/internal/modules/component/handler"
 	"github.com/motain/of-catalog/internal/modules/component/repository"
+	"github.com/motain/of-catalog/internal/services/awsservice"
+	"github.com/motain/of-catalog/internal/services/codesearchservice"
 	"github.com/motain/of-catalog/internal/services/compassservice"
 	"github.com/motain/of-catalog/internal/services/configservice"
 	"github.com/motain/of-catalog/internal/services/factsystem/aggregators"
 	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
 	"github.com/motain/of-catalog/internal/services/factsystem/processor"
+	"github.com/motain/of-catalog/internal/services/factsystem/registry"
 	"github.com/motain/of-catalog/internal/services/factsystem/validators"
 	"github.com/motain/of-catalog/internal/services/githubservice"
 	"github.com/motain/of-catalog/internal/services/jsonservice"
@@ -43,6 +46,15 @@ var ProviderSet = wire.NewSet(
 	prometheusservice.NewPrometheusClient,
 	wire.Bind(new(prometheusservice.PrometheusServiceInterface), new(*prometheusservice.PrometheusService)),
 
+	// Codesearchservice
+	codesearchservice.NewCodeSearchService,
+	wire.Bind(new(codesearchservice.CodeSearchServiceInterface), new(*codesearchservice.CodeSearchService)),
+
+	// Awsservice
+	awsservice.NewAWSService,
+	awsservice.NewAWSClient,
+	wire.Bind(new(awsservice.AWSServiceInterface), new(*awsservice.AWSService)),
+
 	// JSONService
 	jsonservice.NewJSONService,
 
@@ -51,6 +63,8 @@ var ProviderSet = wire.NewSet(
 	repository.NewRepository,
 	wire.Bind(new(repository.RepositoryInterface), new(*repository.Repository)),
 	// Fact System
+	registry.NewRegistry,
+
 	aggregators.NewAggregator,
 	wire.Bind(new(aggregators.AggregatorInterface), new(*aggregators.Aggregator)),
 
@@ -10,11 +10,14 @@ import (
 	"github.com/google/wire"
 	"github.com/motain/of-catalog/internal/modules/component/handler"
 	"github.com/motain/of-catalog/internal/modules/component/repository"
+	"github.com/motain/of-catalog/internal/services/awsservice"
+	"github.com/motain/of-catalog/internal/services/codesearchservice"
 	"github.com/motain/of-catalog/internal/services/compassservice"
 	"github.com/motain/of-catalog/internal/services/configservice"
 	"github.com/motain/of-catalog/internal/services/factsystem/aggregators"
 	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
 	"github.com/motain/of-catalog/internal/services/factsystem/processor"
+	"github.com/motain/of-catalog/internal/services/factsystem/registry"
 	"github.com/motain/of-catalog/internal/services/factsystem/validators"
 	"github.com/motain/of-catalog/internal/services/githubservice"
 	"github.com/motain/of-catalog/internal/services/jsonservice"
@@ -30,15 +33,19 @@ func initializeHandler() *handler.ComputeHandler {
 	httpClientInterface := compassservice.NewHTTPClient(configService)
 	compassService := compassservice.NewCompassService(configService, graphQLClientInterface, httpClientInterface)
 	repositoryRepository := repository.NewRepository(compassService)
-	aggregator := aggregators.NewAggregator()
-	validator := validators.NewValidator()
+	registryRegistry := registry.NewRegistry()
+	aggregator := aggregators.NewAggregator(registryRegistry)
+	validator := validators.NewValidator(registryRegistry)
 	jsonServiceInterface := jsonservice.NewJSONService(configService)
 	keyringService := keyringservice.NewKeyringService()
 	gitHubClientInterface := githubservice.NewGitHubClient(configService, keyringService)
 	gitHubService := githubservice.NewGitHubService(gitHubClientInterface)
 	prometheusClientInterface := prometheusservice.NewPrometheusClient(configService)
 	prometheusService := prometheusservice.NewPrometheusService(prometheusClientInterface)
-	extractor := extractors.NewExtractor(configService, jsonServiceInterface, gitHubService, prometheusService)
+	codeSearchService := codesearchservice.NewCodeSearchService(configService, gitHubService)
+	awsClientInterface := awsservice.NewAWSClient(configService)
+	awsService := awsservice.NewAWSService(awsClientInterface)
+	extractor := extractors.NewExtractor(configService, jsonServiceInterface, gitHubService, prometheusService, codeSearchService, awsService, compassService, registryRegistry)
 	processorProcessor := processor.NewProcessor(aggregator, validator, extractor)
 	computeHandler := handler.NewComputeHandler(repositoryRepository, processorProcessor)
 	return computeHandler
@@ -46,4 +53,4 @@ func initializeHandler() *handler.ComputeHandler {
 
 // wire.go:
 
-var ProviderSet = wire.NewSet(keyringservice.NewKeyringService, wire.Bind(new(keyringservice.KeyringServiceInterface), new(*keyringservice.KeyringService)), configservice.NewConfigService, wire.Bind(new(configservice.ConfigServiceInterface), new(*configservice.ConfigService)), compassservice.NewGraphQLClient, compassservice.NewHTTPClient, compassservice.NewCompassService, wire.Bind(new(compassservice.CompassServiceInterface), new(*compassservice.CompassService)), githubservice.NewGitHubClient, githubservice.NewGitHubService, wire.Bind(new(githubservice.GitHubServiceInterface), new(*githubservice.GitHubService)), prometheusservice.NewPrometheusService, prometheusservice.NewPrometheusClient, wire.Bind(new(prometheusservice.PrometheusServiceInterface), new(*prometheusservice.PrometheusService)), jsonservice.NewJSONService, repository.NewRepository, wire.Bind(new(repository.RepositoryInterface), new(*repository.Repository)), aggregators.NewAggregator, wire.Bind(new(aggregators.AggregatorInterface), new(*aggregators.Aggregator)), extractors.NewExtractor, wire.Bind(new(extractors.ExtractorInterface), new(*extractors.Extractor)), validators.NewValidator, wire.Bind(new(validators.ValidatorInterface), new(*validators.Validator)), processor.NewProcessor, wire.Bind(new(processor.ProcessorInterface), new(*processor.Processor)), handler.NewComputeHandler)
+var ProviderSet = wire.NewSet(keyringservice.NewKeyringService, wire.Bind(new(keyringservice.KeyringServiceInterface), new(*keyringservice.KeyringService)), configservice.NewConfigService, wire.Bind(new(configservice.ConfigServiceInterface), new(*configservice.ConfigService)), compassservice.NewGraphQLClient, compassservice.NewHTTPClient, compassservice.NewCompassService, wire.Bind(new(compassservice.CompassServiceInterface), new(*compassservice.CompassService)), githubservice.NewGitHubClient, githubservice.NewGitHubService, wire.Bind(new(githubservice.GitHubServiceInterface), new(*githubservice.GitHubService)), prometheusservice.NewPrometheusService, prometheusservice.NewPrometheusClient, wire.Bind(new(prometheusservice.PrometheusServiceInterface), new(*prometheusservice.PrometheusService)), codesearchservice.NewCodeSearchService, wire.Bind(new(codesearchservice.CodeSearchServiceInterface), new(*codesearchservice.CodeSearchService)), awsservice.NewAWSService, awsservice.NewAWSClient, wire.Bind(new(awsservice.AWSServiceInterface), new(*awsservice.AWSService)), jsonservice.NewJSONService, repository.NewRepository, wire.Bind(new(repository.RepositoryInterface), new(*repository.Repository)), registry.NewRegistry, aggregators.NewAggregator, wire.Bind(new(aggregators.AggregatorInterface), new(*aggregators.Aggregator)), extractors.NewExtractor, wire.Bind(new(extractors.ExtractorInterface), new(*extractors.Extractor)), validators.NewValidator, wire.Bind(new(validators.ValidatorInterface), new(*validators.Validator)), processor.NewProcessor, wire.Bind(new(processor.ProcessorInterface), new(*processor.Processor)), handler.NewComputeHandler)
@@ -89,8 +89,8 @@ func IsEqualComponent(c1, c2 *ComponentDTO) bool {
 }
 
 type Metadata struct {
-	Name          string `yaml:"name" jsonyaml:"name"`
-	ComponentType string `yaml:"componentType" jsonyaml:"componentType"`
+	Name          string `yaml:"name" json:"name"`
+	ComponentType string `yaml:"componentType" json:"componentType"`
 }
 
 type Spec struct {
@@ -106,9 +106,13 @@ type Spec struct {
 	Links         []Link                      `yaml:"links" json:"links"`
 	Documents     []*Document                 `yaml:"documents" json:"documents"`
 	Labels        []string                    `yaml:"labels" json:"labels"`
-	MetricSources map[string]*MetricSourceDTO `yaml:"metricSources" json:"metricSources"`
+	MetricSources map[string]*MetricSourceDTO `yaml:"metricSources" json:"metricSources,omitempty"`
 	Tribe         string                      `yaml:"tribe" json:"tribe"`
 	Squad         string                      `yaml:"squad" json:"squad"`
+
+	// Per component exemptions and fact overrides, keyed by metric name
+	MetricExemptions map[string]*MetricExemptionDTO `yaml:"metricExemptions,omitempty" json:"metricExemptions,omitempty"`
+	MetricOverrides  map[string]*MetricOverrideDTO  `yaml:"metricOverrides,omitempty" json:"metricOverrides,omitempty"`
 }
 
 type Link struct {
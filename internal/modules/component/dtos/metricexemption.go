@@ -0,0 +1,85 @@
+package dtos
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+)
+
+// ExemptionDateLayout is the layout of the expiry date of exemptions.
+const ExemptionDateLayout = "2006-01-02"
+
+// DefaultExemptionValue is reported for exempted metrics not declaring a value.
+const DefaultExemptionValue = 1.0
+
+// MetricExemptionDTO exempts a component from a metric until the end of the Expires day (YYYY-MM-DD).
+// While the exemption is active the metric is not computed and Value is reported instead.
+type MetricExemptionDTO struct {
+	Reason  string   `yaml:"reason" json:"reason"`
+	Expires string   `yaml:"expires" json:"expires"`
+	Value   *float64 `yaml:"value,omitempty" json:"value,omitempty"`
+}
+
+// MetricOverrideDTO overrides properties of the facts of a metric for a component, facts are
+// referenced by id and only the properties set in the override replace the ones of the metric.
+type MetricOverrideDTO struct {
+	Facts map[string]*fsdtos.Task `yaml:"facts" json:"facts"`
+}
+
+func (e *MetricExemptionDTO) Validate() error {
+	if e.Reason == "" {
+		return errors.New("exemption requires a reason")
+	}
+
+	if _, err := time.Parse(ExemptionDateLayout, e.Expires); err != nil {
+		return fmt.Errorf("invalid exemption expiry date %q, expected YYYY-MM-DD", e.Expires)
+	}
+
+	return nil
+}
+
+// IsActive reports whether the exemption applies at the given time, an exemption is active
+// until the end of its expiry day (UTC).
+func (e *MetricExemptionDTO) IsActive(now time.Time) bool {
+	expires, err := time.Parse(ExemptionDateLayout, e.Expires)
+	if err != nil {
+		return false
+	}
+
+	return now.Before(expires.AddDate(0, 0, 1))
+}
+
+// ReportedValue is the value reported for the metric while the exemption is active.
+func (e *MetricExemptionDTO) ReportedValue() float64 {
+	if e.Value == nil {
+		return DefaultExemptionValue
+	}
+	return *e.Value
+}
+
+// ValidateComponent checks the metric exemptions and overrides of a component definition.
+func ValidateComponent(c *ComponentDTO) error {
+	for metricName, exemption := range c.Spec.MetricExemptions {
+		if exemption == nil {
+			return fmt.Errorf("component %s, metric %s: empty exemption", c.Spec.Name, metricName)
+		}
+		if err := exemption.Validate(); err != nil {
+			return fmt.Errorf("component %s, metric %s: %v", c.Spec.Name, metricName, err)
+		}
+	}
+
+	for metricName, override := range c.Spec.MetricOverrides {
+		if override == nil {
+			continue
+		}
+		for factID, fact := range override.Facts {
+			if fact != nil && (fact.ID != "" || fact.Type != "") {
+				return fmt.Errorf("component %s, metric %s, fact %s: overrides cannot change the id or type of facts", c.Spec.Name, metricName, factID)
+			}
+		}
+	}
+
+	return nil
+}
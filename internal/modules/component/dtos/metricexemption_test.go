@@ -0,0 +1,72 @@
+package dtos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/motain/of-catalog/internal/modules/component/dtos"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricExemptionDTO_IsActive(t *testing.T) {
+	exemption := &dtos.MetricExemptionDTO{Reason: "batch job without autoscaling", Expires: "2026-03-31"}
+
+	assert.True(t, exemption.IsActive(time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC)))
+	assert.False(t, exemption.IsActive(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, dtos.DefaultExemptionValue, exemption.ReportedValue())
+
+	value := 0.5
+	exemption.Value = &value
+	assert.Equal(t, 0.5, exemption.ReportedValue())
+}
+
+func TestValidateComponent(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        dtos.Spec
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			spec: dtos.Spec{
+				MetricExemptions: map[string]*dtos.MetricExemptionDTO{"high-availability": {Reason: "batch job", Expires: "2026-03-31"}},
+				MetricOverrides: map[string]*dtos.MetricOverrideDTO{
+					"adaptive-systems": {Facts: map[string]*fsdtos.Task{"replicas-min": {FilePath: "deploy/app.toml"}}},
+				},
+			},
+		},
+		{
+			name:        "missing reason",
+			spec:        dtos.Spec{MetricExemptions: map[string]*dtos.MetricExemptionDTO{"high-availability": {Expires: "2026-03-31"}}},
+			expectedErr: "component api, metric high-availability: exemption requires a reason",
+		},
+		{
+			name:        "invalid expiry date",
+			spec:        dtos.Spec{MetricExemptions: map[string]*dtos.MetricExemptionDTO{"high-availability": {Reason: "batch job", Expires: "31/03/2026"}}},
+			expectedErr: `component api, metric high-availability: invalid exemption expiry date "31/03/2026", expected YYYY-MM-DD`,
+		},
+		{
+			name: "override changing the type",
+			spec: dtos.Spec{MetricOverrides: map[string]*dtos.MetricOverrideDTO{
+				"adaptive-systems": {Facts: map[string]*fsdtos.Task{"replicas-min": {Type: "validate"}}},
+			}},
+			expectedErr: "component api, metric adaptive-systems, fact replicas-min: overrides cannot change the id or type of facts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component := &dtos.ComponentDTO{Spec: tt.spec}
+			component.Spec.Name = "api"
+
+			err := dtos.ValidateComponent(component)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
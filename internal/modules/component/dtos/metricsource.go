@@ -4,11 +4,17 @@ import fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
 
 type MetricSourceStatus string
 
+// StaleMetricSourceStatus marks metric sources whose facts changed in the metric definition since they were bound,
+// `component bind --refresh-facts` binds their facts again.
+const StaleMetricSourceStatus MetricSourceStatus = "stale"
+
 type MetricSourceDTO struct {
-	ID     string         `yaml:"id"`
-	Name   string         `yaml:"name"`
-	Metric string         `yaml:"metric"`
-	Facts  []*fsdtos.Task `yaml:"facts"`
+	ID        string              `yaml:"id"`
+	Name      string              `yaml:"name"`
+	Metric    string              `yaml:"metric"`
+	Status    MetricSourceStatus  `yaml:"status,omitempty"`
+	Exemption *MetricExemptionDTO `yaml:"exemption,omitempty"`
+	Facts     []*fsdtos.Task      `yaml:"facts"`
 }
 
 func GetMetricSourceUniqueKey(m *MetricSourceDTO) string {
@@ -44,6 +44,11 @@ func (h *ApplyHandler) Apply(ctx context.Context, configRootLocation string, sta
 	if errConfig != nil {
 		log.Fatalf("error: %v", errConfig)
 	}
+	for _, component := range configComponents {
+		if validationErr := dtos.ValidateComponent(component); validationErr != nil {
+			log.Fatalf("error: %v", validationErr)
+		}
+	}
 
 	stateComponents, errState := yaml.Parse(yaml.GetStateInput(stateRootLocation), dtos.GetComponentUniqueKey)
 	if errState != nil {
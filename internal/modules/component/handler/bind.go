@@ -27,29 +27,49 @@ func NewBindHandler(
 	return &BindHandler{github: gh, repository: repository}
 }
 
-func (h *BindHandler) Bind(ctx context.Context, stateRootLocation string) {
+// Bind binds the metrics to the components matching them and unbinds the metrics no longer matching.
+// componentName and metricName, when set, restrict the binding to a component and to a metric, leaving
+// the other metric sources untouched. With plan set, the changes are printed without being applied.
+// With refreshFacts set, only the facts of the stale metric sources are bound again.
+func (h *BindHandler) Bind(ctx context.Context, stateRootLocation string, componentName string, metricName string, plan bool, refreshFacts bool) {
 	components, errCState := yaml.Parse(yaml.GetStateInput(stateRootLocation), dtos.GetComponentUniqueKey)
 	if errCState != nil {
 		log.Fatalf("error: %v", errCState)
 	}
 
-	metricsMap := h.getMetricsGroupedByCompoentType(stateRootLocation)
+	metrics, errMState := yaml.Parse(yaml.GetStateInput(stateRootLocation), metricdtos.GetMetricUniqueKey)
+	if errMState != nil {
+		log.Fatalf("error: %v", errMState)
+	}
 
-	for _, component := range components {
-		for metricName, metricSource := range component.Spec.MetricSources {
-			if _, exists := metricsMap[component.Metadata.ComponentType][metricName]; !exists {
-				errDelete := h.repository.UnbindMetric(ctx, MetricSourceDTOToResource(metricSource))
-				if errDelete != nil {
-					fmt.Printf("Failed to delete metric source %s: %v\n", metricSource.Name, errDelete)
-				}
-			}
-		}
+	if componentName != "" && components[componentName] == nil {
+		log.Fatalf("component %s not found", componentName)
+	}
+	if metricName != "" && !hasMetric(metrics, metricName) {
+		log.Fatalf("metric %s not found", metricName)
+	}
 
-		for metricName, metric := range metricsMap[component.Metadata.ComponentType] {
-			bindErr := h.handleBind(ctx, component, metric)
-			if bindErr != nil {
-				fmt.Printf("Failed to bind metric %s to component %s: %v\n", metricName, component.Metadata.Name, bindErr)
-			}
+	metricsMap := h.getMetricsGroupedByComponent(metrics, components)
+
+	factTemplates, errTState := yaml.Parse(yaml.GetStateInput(stateRootLocation), metricdtos.GetFactTemplateUniqueKey)
+	if errTState != nil {
+		log.Fatalf("error: %v", errTState)
+	}
+
+	plans := newBindPlans(components, metricsMap, componentName, metricName)
+
+	switch {
+	case refreshFacts:
+		h.refreshStaleFacts(plans, factTemplates, plan)
+		if plan {
+			return
+		}
+	case plan:
+		printBindPlans(plans)
+		return
+	default:
+		for _, bindPlan := range plans {
+			h.applyBindPlan(ctx, bindPlan, factTemplates)
 		}
 	}
 
@@ -66,38 +86,81 @@ func (h *BindHandler) Bind(ctx context.Context, stateRootLocation string) {
 	}
 }
 
-func (*BindHandler) getMetricsGroupedByCompoentType(
-	stateRootLocation string,
-) map[string]map[string]*metricdtos.MetricDTO {
-	metrics, errMState := yaml.Parse(yaml.GetStateInput(stateRootLocation), metricdtos.GetMetricUniqueKey)
-	if errMState != nil {
-		log.Fatalf("error: %v", errMState)
+func (h *BindHandler) applyBindPlan(ctx context.Context, plan *bindPlan, factTemplates map[string]*metricdtos.FactTemplateDTO) {
+	component := plan.component
+	for _, metricName := range plan.remove {
+		metricSource := component.Spec.MetricSources[metricName]
+		errDelete := h.repository.UnbindMetric(ctx, MetricSourceDTOToResource(metricSource))
+		if errDelete != nil {
+			fmt.Printf("Failed to delete metric source %s: %v\n", metricSource.Name, errDelete)
+			continue
+		}
+		delete(component.Spec.MetricSources, metricName)
+	}
+
+	for _, metric := range append(plan.create, plan.refresh...) {
+		bindErr := h.handleBind(ctx, component, metric, factTemplates)
+		if bindErr != nil {
+			fmt.Printf("Failed to bind metric %s to component %s: %v\n", metric.Metadata.Name, component.Metadata.Name, bindErr)
+		}
 	}
+}
 
+// getMetricsGroupedByComponent returns the metrics bound to every component, keyed by the unique key
+// of the component, evaluating the componentType and the selector of the metrics.
+func (*BindHandler) getMetricsGroupedByComponent(
+	metrics map[string]*metricdtos.MetricDTO,
+	components map[string]*dtos.ComponentDTO,
+) map[string]map[string]*metricdtos.MetricDTO {
 	metricsMap := make(map[string]map[string]*metricdtos.MetricDTO)
-	for _, metric := range metrics {
-		for _, componentType := range metric.Metadata.ComponentType {
-			if _, exists := metricsMap[componentType]; !exists {
-				metricsMap[componentType] = make(map[string]*metricdtos.MetricDTO)
+	for componentKey, component := range components {
+		metricsMap[componentKey] = make(map[string]*metricdtos.MetricDTO)
+		for _, metric := range metrics {
+			if utils.MatchesMetric(metric, component) {
+				metricsMap[componentKey][metric.Metadata.Name] = metric
 			}
-			metricsMap[componentType][metric.Metadata.Name] = metric
 		}
 	}
 
 	return metricsMap
 }
 
-func (h *BindHandler) handleBind(ctx context.Context, component *dtos.ComponentDTO, metric *metricdtos.MetricDTO) error {
+func hasMetric(metrics map[string]*metricdtos.MetricDTO, metricName string) bool {
+	for _, metric := range metrics {
+		if metric.Metadata.Name == metricName {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *BindHandler) handleBind(
+	ctx context.Context,
+	component *dtos.ComponentDTO,
+	metric *metricdtos.MetricDTO,
+	factTemplates map[string]*metricdtos.FactTemplateDTO,
+) error {
 	fmt.Printf("Binding component %s to metric %s\n", component.Metadata.Name, metric.Metadata.Name)
 
 	metricName := metric.Metadata.Name
 	componentName := component.Metadata.Name
 	identifier := utils.GetMetricSourceIdentifier(metricName, componentName, component.Metadata.ComponentType)
-	tasks := h.prepareSourceMetricFacts(metric.Metadata.Facts, *component)
+
+	tasks, prepareErr := h.prepareMetricFacts(component, metric, factTemplates)
+	if prepareErr != nil {
+		return prepareErr
+	}
+
+	exemption := component.Spec.MetricExemptions[metricName]
+	if exemption != nil {
+		fmt.Printf("Metric %s is exempted for component %s until %s: %s\n", metricName, componentName, exemption.Expires, exemption.Reason)
+	}
 
 	if _, exists := component.Spec.MetricSources[metricName]; exists {
 		component.Spec.MetricSources[metricName].Facts = tasks
 		component.Spec.MetricSources[metricName].Name = identifier
+		component.Spec.MetricSources[metricName].Exemption = exemption
+		component.Spec.MetricSources[metricName].Status = ""
 		return nil
 	}
 
@@ -111,61 +174,86 @@ func (h *BindHandler) handleBind(ctx context.Context, component *dtos.ComponentD
 	}
 
 	component.Spec.MetricSources[metricName] = &dtos.MetricSourceDTO{
-		ID:     id,
-		Name:   identifier,
-		Metric: metric.Spec.ID,
-		Facts:  tasks,
+		ID:        id,
+		Name:      identifier,
+		Metric:    metric.Spec.ID,
+		Exemption: exemption,
+		Facts:     tasks,
 	}
 
 	return nil
 }
 
-func (h *BindHandler) prepareSourceMetricFacts(tasks []*fsdtos.Task, component dtos.ComponentDTO) []*fsdtos.Task {
+// prepareMetricFacts returns the facts of the metric bound to the component, with the fact templates expanded.
+func (h *BindHandler) prepareMetricFacts(
+	component *dtos.ComponentDTO,
+	metric *metricdtos.MetricDTO,
+	factTemplates map[string]*metricdtos.FactTemplateDTO,
+) ([]*fsdtos.Task, error) {
+	facts, expandErr := metricdtos.ExpandFactTemplates(metric.Metadata.Facts, factTemplates)
+	if expandErr != nil {
+		return nil, expandErr
+	}
+
+	return h.prepareSourceMetricFacts(facts, *component, component.Spec.MetricOverrides[metric.Metadata.Name])
+}
+
+func (h *BindHandler) prepareSourceMetricFacts(
+	tasks []*fsdtos.Task,
+	component dtos.ComponentDTO,
+	override *dtos.MetricOverrideDTO,
+) ([]*fsdtos.Task, error) {
+	var factOverrides map[string]*fsdtos.Task
+	if override != nil {
+		factOverrides = override.Facts
+	}
+
 	processedFacts := make([]*fsdtos.Task, len(tasks))
+	overridden := make(map[string]bool, len(factOverrides))
 	for i, task := range tasks {
-		processedFacts[i] = h.prepareSourceMetricFact(task, component)
+		var factOverride *fsdtos.Task
+		if task != nil {
+			factOverride = factOverrides[task.ID]
+			overridden[task.ID] = factOverride != nil
+		}
+
+		processedFact, prepareErr := h.prepareSourceMetricFact(task, component, factOverride)
+		if prepareErr != nil {
+			return nil, prepareErr
+		}
+		processedFacts[i] = processedFact
 	}
-	return processedFacts
+
+	for factID := range factOverrides {
+		if !overridden[factID] {
+			return nil, fmt.Errorf("override of unknown fact %s", factID)
+		}
+	}
+
+	return processedFacts, nil
 }
 
-func (h *BindHandler) prepareSourceMetricFact(task *fsdtos.Task, component dtos.ComponentDTO) *fsdtos.Task {
+// prepareSourceMetricFact returns the fact bound to the component: the override of the component
+// is applied first, then the placeholders of every property are replaced with the values of the component.
+func (h *BindHandler) prepareSourceMetricFact(task *fsdtos.Task, component dtos.ComponentDTO, override *fsdtos.Task) (*fsdtos.Task, error) {
 	if task == nil {
-		return nil
+		return nil, nil
+	}
+
+	overridden, overrideErr := utils.ApplyFactOverride(task, override)
+	if overrideErr != nil {
+		return nil, fmt.Errorf("failed to override fact %s: %v", task.ID, overrideErr)
+	}
+
+	processedFact, replaceErr := utils.ReplaceFactPlaceholders(overridden, component)
+	if replaceErr != nil {
+		return nil, fmt.Errorf("fact %s: %v", task.ID, replaceErr)
+	}
+
+	// Compass facts inspect the bound component unless they target another one
+	if fsdtos.TaskSource(processedFact.Source) == fsdtos.CompassTaskSource && processedFact.ComponentID == "" {
+		processedFact.ComponentID = component.Spec.ID
 	}
 
-	// if fact.URI != "" {
-	// 	fmt.Printf("Processing fact %s for component %s\n", fact.Name, component.Metadata.Name)
-	// 	fmt.Printf("Fact URI: %s\n", fact.URI)
-	// 	parsed := utils.ReplaceMetricFactPlaceholders(fact.URI, component)
-	// 	fmt.Printf("Fact URI: %s\n", parsed)
-	// }
-
-	processedFact := fsdtos.Task{
-		ID:     task.ID,
-		Name:   task.Name,
-		Source: task.Source,
-		URI:    utils.ReplaceMetricFactPlaceholders(task.URI, component),
-		Auth:   task.Auth,
-		// ComponentName: utils.ReplaceMetricFactPlaceholders(task.ComponentName, component),
-		Repo:            utils.ReplaceMetricFactPlaceholders(task.Repo, component),
-		Type:            task.Type,
-		FilePath:        task.FilePath,
-		JSONPath:        task.JSONPath,
-		Rule:            task.Rule,
-		Pattern:         utils.ReplaceMetricFactPlaceholders(task.Pattern, component),
-		DependsOn:       task.DependsOn,
-		Method:          task.Method,
-		SearchString:    task.SearchString,
-		PrometheusQuery: utils.ReplaceMetricFactPlaceholders(task.PrometheusQuery, component),
-
-		// Are these still worth it?
-		// RegexPattern:     task.RegexPattern,
-		// RepoProperty:     task.RepoProperty,
-		// ReposSearchQuery: task.ReposSearchQuery,
-
-		// I need to reintegrate this !!
-		// ExpectedFormula:  task.ExpectedFormula,
-	}
-
-	return &processedFact
+	return processedFact, nil
 }
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/motain/of-catalog/internal/modules/component/dtos"
+	metricdtos "github.com/motain/of-catalog/internal/modules/metric/dtos"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+)
+
+// bindPlan lists the metric sources of a component to create, to refresh (existing metric sources
+// whose facts are bound again) and to remove.
+type bindPlan struct {
+	component *dtos.ComponentDTO
+	create    []*metricdtos.MetricDTO
+	refresh   []*metricdtos.MetricDTO
+	remove    []string
+}
+
+// newBindPlans returns the plans of the components sorted by name, restricted to componentName and
+// to metricName when set. metricsMap holds the metrics matching every component, keyed by component.
+func newBindPlans(
+	components map[string]*dtos.ComponentDTO,
+	metricsMap map[string]map[string]*metricdtos.MetricDTO,
+	componentName string,
+	metricName string,
+) []*bindPlan {
+	plans := make([]*bindPlan, 0, len(components))
+	for componentKey, component := range components {
+		if componentName != "" && componentKey != componentName {
+			continue
+		}
+		plans = append(plans, newBindPlan(component, metricsMap[componentKey], metricName))
+	}
+	slices.SortFunc(plans, func(a, b *bindPlan) int {
+		return strings.Compare(a.component.Metadata.Name, b.component.Metadata.Name)
+	})
+
+	return plans
+}
+
+// newBindPlan compares the metric sources of the component with the metrics matching it, restricted
+// to metricName when set.
+func newBindPlan(component *dtos.ComponentDTO, metrics map[string]*metricdtos.MetricDTO, metricName string) *bindPlan {
+	plan := &bindPlan{component: component}
+
+	for name := range component.Spec.MetricSources {
+		if metricName != "" && name != metricName {
+			continue
+		}
+		if _, exists := metrics[name]; !exists {
+			plan.remove = append(plan.remove, name)
+		}
+	}
+
+	for name, metric := range metrics {
+		if metricName != "" && name != metricName {
+			continue
+		}
+		if _, exists := component.Spec.MetricSources[name]; exists {
+			plan.refresh = append(plan.refresh, metric)
+		} else {
+			plan.create = append(plan.create, metric)
+		}
+	}
+
+	slices.Sort(plan.remove)
+	byName := func(a, b *metricdtos.MetricDTO) int { return strings.Compare(a.Metadata.Name, b.Metadata.Name) }
+	slices.SortFunc(plan.create, byName)
+	slices.SortFunc(plan.refresh, byName)
+
+	return plan
+}
+
+func (p *bindPlan) isEmpty() bool {
+	return len(p.create) == 0 && len(p.remove) == 0
+}
+
+// printBindPlans prints the metric sources to create and to remove per component, components
+// without changes are omitted.
+func printBindPlans(plans []*bindPlan) {
+	var created, removed, refreshed int
+	for _, plan := range plans {
+		created += len(plan.create)
+		removed += len(plan.remove)
+		refreshed += len(plan.refresh)
+		if plan.isEmpty() {
+			continue
+		}
+
+		fmt.Printf("Component %s:\n", plan.component.Metadata.Name)
+		for _, metric := range plan.create {
+			fmt.Printf("  + %s\n", metric.Metadata.Name)
+		}
+		for _, metricName := range plan.remove {
+			fmt.Printf("  - %s\n", metricName)
+		}
+	}
+
+	fmt.Printf("Plan: %d metric sources to create, %d to remove, %d to refresh.\n", created, removed, refreshed)
+}
+
+// refreshStaleFacts binds again the facts of the stale metric sources of the plans and prints the facts
+// added (+), removed (-) and changed (~) per metric source. With plan set, the facts are left untouched.
+func (h *BindHandler) refreshStaleFacts(plans []*bindPlan, factTemplates map[string]*metricdtos.FactTemplateDTO, plan bool) {
+	refreshed, changed := 0, 0
+	for _, bindPlan := range plans {
+		component := bindPlan.component
+		for _, metricName := range bindPlan.remove {
+			if component.Spec.MetricSources[metricName].Status == dtos.StaleMetricSourceStatus {
+				fmt.Printf("Component %s, metric %s: the metric no longer matches the component, run bind to remove it\n", component.Metadata.Name, metricName)
+			}
+		}
+
+		for _, metric := range bindPlan.refresh {
+			metricSource := component.Spec.MetricSources[metric.Metadata.Name]
+			if metricSource.Status != dtos.StaleMetricSourceStatus {
+				continue
+			}
+
+			tasks, prepareErr := h.prepareMetricFacts(component, metric, factTemplates)
+			if prepareErr != nil {
+				fmt.Printf("Failed to refresh facts of metric %s for component %s: %v\n", metric.Metadata.Name, component.Metadata.Name, prepareErr)
+				continue
+			}
+
+			refreshed += 1
+			if printFactChanges(component.Metadata.Name, metric.Metadata.Name, metricSource.Facts, tasks) {
+				changed += 1
+			}
+
+			if !plan {
+				metricSource.Facts = tasks
+				metricSource.Status = ""
+			}
+		}
+	}
+
+	fmt.Printf("Refresh: %d stale metric sources, %d with changed facts.\n", refreshed, changed)
+}
+
+// printFactChanges prints the facts added, removed and changed between two bindings of a metric,
+// facts are matched by id. It reports whether any fact changed.
+func printFactChanges(componentName, metricName string, previous, current []*fsdtos.Task) bool {
+	previousByID := make(map[string]*fsdtos.Task, len(previous))
+	for _, fact := range previous {
+		previousByID[fact.ID] = fact
+	}
+	currentIDs := make(map[string]bool, len(current))
+
+	var changes []string
+	for _, fact := range current {
+		currentIDs[fact.ID] = true
+		previousFact, found := previousByID[fact.ID]
+		switch {
+		case !found:
+			changes = append(changes, "  + "+fact.ID)
+		case !previousFact.IsEqual(fact):
+			changes = append(changes, "  ~ "+fact.ID)
+		}
+	}
+	for _, fact := range previous {
+		if !currentIDs[fact.ID] {
+			changes = append(changes, "  - "+fact.ID)
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Printf("Component %s, metric %s: facts unchanged\n", componentName, metricName)
+		return false
+	}
+
+	fmt.Printf("Component %s, metric %s:\n%s\n", componentName, metricName, strings.Join(changes, "\n"))
+	return true
+}
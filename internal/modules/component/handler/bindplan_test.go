@@ -0,0 +1,389 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/modules/component/dtos"
+	repositorymocks "github.com/motain/of-catalog/internal/modules/component/repository/mocks"
+	metricdtos "github.com/motain/of-catalog/internal/modules/metric/dtos"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/utils/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestMetric(name string) *metricdtos.MetricDTO {
+	metric := &metricdtos.MetricDTO{Kind: "Metric"}
+	metric.Metadata.Name = name
+	metric.Spec.Name = name
+	metric.Metadata.ComponentType = []string{"service"}
+	return metric
+}
+
+func newTestComponent(name string, metricSources ...string) *dtos.ComponentDTO {
+	component := &dtos.ComponentDTO{
+		Kind:     "Component",
+		Metadata: dtos.Metadata{Name: name, ComponentType: "service"},
+		Spec:     dtos.Spec{Name: name, MetricSources: map[string]*dtos.MetricSourceDTO{}},
+	}
+	for _, metricName := range metricSources {
+		component.Spec.MetricSources[metricName] = &dtos.MetricSourceDTO{Name: name + "-" + metricName}
+	}
+	return component
+}
+
+// captureStdout returns what fn prints on the standard output.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	fn()
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	return string(output)
+}
+
+// chdirTemp runs the test in a temporary directory, where the state is read and written.
+func chdirTemp(t *testing.T) {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed to get the working directory: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("failed to change the working directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// planNames flattens the plans as component name to create, refresh and remove metric names.
+func planNames(plans []*bindPlan) map[string][3][]string {
+	names := make(map[string][3][]string, len(plans))
+	for _, plan := range plans {
+		var planned [3][]string
+		for _, metric := range plan.create {
+			planned[0] = append(planned[0], metric.Metadata.Name)
+		}
+		for _, metric := range plan.refresh {
+			planned[1] = append(planned[1], metric.Metadata.Name)
+		}
+		planned[2] = plan.remove
+		names[plan.component.Metadata.Name] = planned
+	}
+	return names
+}
+
+func TestNewBindPlans(t *testing.T) {
+	newState := func() (map[string]*dtos.ComponentDTO, map[string]map[string]*metricdtos.MetricDTO) {
+		components := map[string]*dtos.ComponentDTO{
+			"api":    newTestComponent("api", "coverage", "legacy"),
+			"worker": newTestComponent("worker"),
+		}
+		coverage, owner := newTestMetric("coverage"), newTestMetric("owner")
+		metricsMap := map[string]map[string]*metricdtos.MetricDTO{
+			"api":    {"coverage": coverage, "owner": owner},
+			"worker": {"owner": owner},
+		}
+		return components, metricsMap
+	}
+
+	tests := []struct {
+		name          string
+		componentName string
+		metricName    string
+		expected      map[string][3][]string
+		expectedOrder []string
+	}{
+		{
+			name: "all components and metrics",
+			expected: map[string][3][]string{
+				"api":    {{"owner"}, {"coverage"}, {"legacy"}},
+				"worker": {{"owner"}, nil, nil},
+			},
+			expectedOrder: []string{"api", "worker"},
+		},
+		{
+			name:          "component filter",
+			componentName: "worker",
+			expected: map[string][3][]string{
+				"worker": {{"owner"}, nil, nil},
+			},
+			expectedOrder: []string{"worker"},
+		},
+		{
+			name:       "metric filter",
+			metricName: "legacy",
+			expected: map[string][3][]string{
+				"api":    {nil, nil, {"legacy"}},
+				"worker": {nil, nil, nil},
+			},
+			expectedOrder: []string{"api", "worker"},
+		},
+		{
+			name:          "component and metric filters",
+			componentName: "api",
+			metricName:    "coverage",
+			expected: map[string][3][]string{
+				"api": {nil, {"coverage"}, nil},
+			},
+			expectedOrder: []string{"api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components, metricsMap := newState()
+			plans := newBindPlans(components, metricsMap, tt.componentName, tt.metricName)
+
+			assert.Equal(t, tt.expected, planNames(plans))
+			order := make([]string, len(plans))
+			for i, plan := range plans {
+				order[i] = plan.component.Metadata.Name
+			}
+			assert.Equal(t, tt.expectedOrder, order)
+		})
+	}
+}
+
+func TestPrintBindPlans(t *testing.T) {
+	plans := []*bindPlan{
+		{
+			component: newTestComponent("api"),
+			create:    []*metricdtos.MetricDTO{newTestMetric("owner")},
+			refresh:   []*metricdtos.MetricDTO{newTestMetric("coverage")},
+			remove:    []string{"legacy"},
+		},
+		{
+			component: newTestComponent("worker"),
+			refresh:   []*metricdtos.MetricDTO{newTestMetric("owner")},
+		},
+	}
+
+	output := captureStdout(t, func() { printBindPlans(plans) })
+
+	expected := "Component api:\n" +
+		"  + owner\n" +
+		"  - legacy\n" +
+		"Plan: 1 metric sources to create, 1 to remove, 2 to refresh.\n"
+	assert.Equal(t, expected, output)
+}
+
+func TestBindHandler_ApplyBindPlanRemove(t *testing.T) {
+	tests := []struct {
+		name            string
+		unbindErr       error
+		expectedSources []string
+	}{
+		{
+			name:            "removes the unbound metric sources",
+			expectedSources: []string{"coverage"},
+		},
+		{
+			name:            "keeps the metric sources failing to unbind",
+			unbindErr:       errors.New("unbind failed"),
+			expectedSources: []string{"coverage", "legacy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepository := repositorymocks.NewMockRepositoryInterface(ctrl)
+			mockRepository.EXPECT().UnbindMetric(gomock.Any(), gomock.Any()).Return(tt.unbindErr)
+
+			component := newTestComponent("api", "coverage", "legacy")
+			handler := NewBindHandler(nil, mockRepository)
+			captureStdout(t, func() {
+				handler.applyBindPlan(context.Background(), &bindPlan{component: component, remove: []string{"legacy"}}, nil)
+			})
+
+			var sources []string
+			for name := range component.Spec.MetricSources {
+				sources = append(sources, name)
+			}
+			assert.ElementsMatch(t, tt.expectedSources, sources)
+		})
+	}
+}
+
+// newRefreshState returns a component with a stale metric source (coverage) whose facts changed and
+// an up to date metric source (owner), with the metrics bound to it.
+func newRefreshState() (*dtos.ComponentDTO, []*metricdtos.MetricDTO) {
+	component := newTestComponent("api", "coverage", "owner")
+	component.Spec.MetricSources["coverage"].Status = dtos.StaleMetricSourceStatus
+	component.Spec.MetricSources["coverage"].Facts = []*fsdtos.Task{
+		{ID: "read", Type: "extract", Source: "local", FilePath: "app.toml"},
+		{ID: "old", Type: "validate", Rule: "notempty"},
+	}
+	component.Spec.MetricSources["owner"].Facts = []*fsdtos.Task{{ID: "read", Type: "extract", Source: "local", FilePath: "CODEOWNERS"}}
+
+	coverage, owner := newTestMetric("coverage"), newTestMetric("owner")
+	coverage.Metadata.Facts = []*fsdtos.Task{
+		{ID: "read", Type: "extract", Source: "local", FilePath: "services/${Metadata.Name}/app.toml"},
+		{ID: "new", Type: "validate", Rule: "unique"},
+	}
+	owner.Metadata.Facts = []*fsdtos.Task{{ID: "read", Type: "extract", Source: "local", FilePath: "OWNERS"}}
+
+	return component, []*metricdtos.MetricDTO{coverage, owner}
+}
+
+func TestBindHandler_RefreshStaleFacts(t *testing.T) {
+	expectedOutput := "Component api, metric coverage:\n" +
+		"  ~ read\n" +
+		"  + new\n" +
+		"  - old\n" +
+		"Refresh: 1 stale metric sources, 1 with changed facts.\n"
+
+	tests := []struct {
+		name          string
+		plan          bool
+		expectedFacts []*fsdtos.Task
+		expectedStale bool
+	}{
+		{
+			name: "binds the facts of the stale metric sources again",
+			expectedFacts: []*fsdtos.Task{
+				{ID: "read", Type: "extract", Source: "local", FilePath: "services/api/app.toml"},
+				{ID: "new", Type: "validate", Rule: "unique"},
+			},
+		},
+		{
+			name: "plan leaves the metric sources untouched",
+			plan: true,
+			expectedFacts: []*fsdtos.Task{
+				{ID: "read", Type: "extract", Source: "local", FilePath: "app.toml"},
+				{ID: "old", Type: "validate", Rule: "notempty"},
+			},
+			expectedStale: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, metrics := newRefreshState()
+			plans := []*bindPlan{{component: component, refresh: metrics}}
+
+			handler := NewBindHandler(nil, nil)
+			output := captureStdout(t, func() { handler.refreshStaleFacts(plans, nil, tt.plan) })
+
+			assert.Equal(t, expectedOutput, output)
+			coverage := component.Spec.MetricSources["coverage"]
+			assert.Equal(t, tt.expectedFacts, coverage.Facts)
+			assert.Equal(t, tt.expectedStale, coverage.Status == dtos.StaleMetricSourceStatus)
+
+			owner := component.Spec.MetricSources["owner"]
+			assert.Equal(t, []*fsdtos.Task{{ID: "read", Type: "extract", Source: "local", FilePath: "CODEOWNERS"}}, owner.Facts)
+			assert.Empty(t, owner.Status)
+		})
+	}
+}
+
+func TestBindHandler_BindRefreshFacts(t *testing.T) {
+	tests := []struct {
+		name             string
+		plan             bool
+		expectedFilePath string
+		expectedStatus   dtos.MetricSourceStatus
+	}{
+		{
+			name:             "writes the refreshed facts",
+			expectedFilePath: "services/api/app.toml",
+		},
+		{
+			name:             "plan does not write the state",
+			plan:             true,
+			expectedFilePath: "app.toml",
+			expectedStatus:   dtos.StaleMetricSourceStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chdirTemp(t)
+			component, metrics := newRefreshState()
+			require.NoError(t, yaml.WriteState([]*dtos.ComponentDTO{component}))
+			require.NoError(t, yaml.WriteState(metrics))
+
+			handler := NewBindHandler(nil, nil)
+			captureStdout(t, func() { handler.Bind(context.Background(), yaml.StateLocation, "", "", tt.plan, true) })
+
+			components, err := yaml.Parse(yaml.GetStateInput(yaml.StateLocation), dtos.GetComponentUniqueKey)
+			require.NoError(t, err)
+			metricSources := components["api"].Spec.MetricSources
+			assert.Equal(t, tt.expectedFilePath, metricSources["coverage"].Facts[0].FilePath)
+			assert.Equal(t, tt.expectedStatus, metricSources["coverage"].Status)
+			// metric sources which are not stale keep their facts until the next bind
+			assert.Equal(t, "CODEOWNERS", metricSources["owner"].Facts[0].FilePath)
+		})
+	}
+}
+
+func TestPrintFactChanges(t *testing.T) {
+	tests := []struct {
+		name            string
+		previous        []*fsdtos.Task
+		current         []*fsdtos.Task
+		expectedChanged bool
+		expectedOutput  string
+	}{
+		{
+			name:            "unchanged",
+			previous:        []*fsdtos.Task{{ID: "read", FilePath: "app.toml"}},
+			current:         []*fsdtos.Task{{ID: "read", FilePath: "app.toml"}},
+			expectedChanged: false,
+			expectedOutput:  "Component api, metric coverage: facts unchanged\n",
+		},
+		{
+			name:            "added",
+			previous:        []*fsdtos.Task{{ID: "read"}},
+			current:         []*fsdtos.Task{{ID: "read"}, {ID: "check"}},
+			expectedChanged: true,
+			expectedOutput:  "Component api, metric coverage:\n  + check\n",
+		},
+		{
+			name:            "removed",
+			previous:        []*fsdtos.Task{{ID: "read"}, {ID: "check"}},
+			current:         []*fsdtos.Task{{ID: "read"}},
+			expectedChanged: true,
+			expectedOutput:  "Component api, metric coverage:\n  - check\n",
+		},
+		{
+			name:            "changed",
+			previous:        []*fsdtos.Task{{ID: "read", FilePath: "app.toml"}},
+			current:         []*fsdtos.Task{{ID: "read", FilePath: "services/api/app.toml"}},
+			expectedChanged: true,
+			expectedOutput:  "Component api, metric coverage:\n  ~ read\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changed bool
+			output := captureStdout(t, func() {
+				changed = printFactChanges("api", "coverage", tt.previous, tt.current)
+			})
+
+			assert.Equal(t, tt.expectedChanged, changed)
+			assert.Equal(t, tt.expectedOutput, output)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"github.com/motain/of-catalog/internal/modules/component/dtos"
 	"github.com/motain/of-catalog/internal/modules/component/repository"
 	"github.com/motain/of-catalog/internal/modules/component/resources"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
 	"github.com/motain/of-catalog/internal/services/factsystem/processor"
 	"github.com/motain/of-catalog/internal/utils/yaml"
 )
@@ -60,11 +63,37 @@ func (h *ComputeHandler) computeMetric(ctx context.Context, component *dtos.Comp
 		return fmt.Errorf("error: metric source not found for metric %s", metricName)
 	}
 
-	metricValue, processErr := h.factProcessor.Process(ctx, metricSource.Facts)
+	if metricSource.Status == dtos.StaleMetricSourceStatus {
+		fmt.Printf("Facts of metric '%s' changed since it was bound, run `component bind --refresh-facts` to compute the new facts\n", metricName)
+	}
+
+	if exemption := metricSource.Exemption; exemption != nil {
+		if exemption.IsActive(time.Now()) {
+			fmt.Printf("Metric '%s' is exempted until %s (%s), reporting %v\n", metricName, exemption.Expires, exemption.Reason, exemption.ReportedValue())
+			return h.push(ctx, metricSource, exemption.ReportedValue())
+		}
+		fmt.Printf("Exemption of metric '%s' expired on %s, computing the metric\n", metricName, exemption.Expires)
+	}
+
+	componentDefinition, definitionErr := componentToFactJSON(component)
+	if definitionErr != nil {
+		return definitionErr
+	}
+
+	metricValue, processErr := h.factProcessor.Process(fsdtos.WithComponent(ctx, componentDefinition), metricSource.Facts)
+	if errors.Is(processErr, processor.ErrNoResult) {
+		// a failing 0 rather than the last value pushed
+		fmt.Printf("Metric '%s' has no result (%v), reporting 0\n", metricName, processErr)
+		return h.push(ctx, metricSource, 0)
+	}
 	if processErr != nil {
 		return fmt.Errorf("%v", processErr)
 	}
 
+	return h.push(ctx, metricSource, metricValue)
+}
+
+func (h *ComputeHandler) push(ctx context.Context, metricSource *dtos.MetricSourceDTO, metricValue float64) error {
 	pushErr := h.repository.Push(ctx, MetricSourceDTOToResource(metricSource), metricValue, time.Now())
 	if pushErr != nil {
 		return fmt.Errorf("error: %v", pushErr)
@@ -73,6 +102,22 @@ func (h *ComputeHandler) computeMetric(ctx context.Context, component *dtos.Comp
 	return nil
 }
 
+// componentToFactJSON encodes the component definition exposed to the facts of source component.
+// Metric sources, exemptions and overrides are left out, they configure the metrics and are not catalog data.
+func componentToFactJSON(component *dtos.ComponentDTO) ([]byte, error) {
+	definition := *component
+	definition.Spec.MetricSources = nil
+	definition.Spec.MetricExemptions = nil
+	definition.Spec.MetricOverrides = nil
+
+	componentJSON, marshalErr := json.Marshal(definition)
+	if marshalErr != nil {
+		return nil, fmt.Errorf("failed to encode component %s: %v", component.Spec.Name, marshalErr)
+	}
+
+	return componentJSON, nil
+}
+
 func MetricSourceDTOToResource(metricSource *dtos.MetricSourceDTO) resources.MetricSource {
 	return resources.MetricSource{
 		ID:     metricSource.ID,
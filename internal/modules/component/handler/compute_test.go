@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	repositorymocks "github.com/motain/of-catalog/internal/modules/component/repository/mocks"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/processor"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// stubProcessor returns the same value and error for every metric.
+type stubProcessor struct {
+	value float64
+	err   error
+}
+
+func (p stubProcessor) Process(context.Context, []*fsdtos.Task) (float64, error) {
+	return p.value, p.err
+}
+
+func TestComputeHandler_ComputeMetricWithoutResult(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := repositorymocks.NewMockRepositoryInterface(ctrl)
+	mockRepository.EXPECT().Push(gomock.Any(), gomock.Any(), 0.0, gomock.Any()).Return(nil)
+
+	component := newTestComponent("api", "coverage")
+	handler := NewComputeHandler(mockRepository, stubProcessor{err: fmt.Errorf("fact read: %w", processor.ErrNoResult)})
+
+	var computeErr error
+	output := captureStdout(t, func() { computeErr = handler.computeMetric(context.Background(), component, "coverage") })
+
+	assert.NoError(t, computeErr)
+	assert.Contains(t, output, "Metric 'coverage' has no result (fact read: no result), reporting 0")
+}
+
+func TestComputeHandler_ComputeMetricError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepository := repositorymocks.NewMockRepositoryInterface(ctrl)
+
+	component := newTestComponent("api", "coverage")
+	handler := NewComputeHandler(mockRepository, stubProcessor{err: fmt.Errorf("invalid metric result")})
+
+	err := handler.computeMetric(context.Background(), component, "coverage")
+	assert.EqualError(t, err, "invalid metric result")
+}
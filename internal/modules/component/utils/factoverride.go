@@ -0,0 +1,34 @@
+package utils
+
+import (
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"gopkg.in/yaml.v3"
+)
+
+// ApplyFactOverride returns a copy of the fact with the properties set in the override.
+// Maps are merged key by key, other properties are replaced. The fact shared across components
+// is left untouched.
+func ApplyFactOverride(fact *fsdtos.Task, override *fsdtos.Task) (*fsdtos.Task, error) {
+	if override == nil {
+		return fact, nil
+	}
+
+	factYAML, marshalErr := yaml.Marshal(fact)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+	overrideYAML, marshalErr := yaml.Marshal(override)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+
+	var overridden fsdtos.Task
+	if err := yaml.Unmarshal(factYAML, &overridden); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(overrideYAML, &overridden); err != nil {
+		return nil, err
+	}
+
+	return &overridden, nil
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyFactOverride(t *testing.T) {
+	fact := &fsdtos.Task{
+		ID:        "replicas-min",
+		Type:      "extract",
+		Source:    "github",
+		Repo:      "${Metadata.Name}",
+		FilePath:  "app.toml",
+		Rule:      "jsonpath",
+		JSONPath:  ".service.replicas_min >= 3",
+		Headers:   map[string]string{"Accept": "application/json", "X-Team": "core"},
+		DependsOn: []string{"a", "b"},
+	}
+
+	overridden, err := ApplyFactOverride(fact, &fsdtos.Task{
+		Repo:      "legacy-monorepo",
+		FilePath:  "services/${Metadata.Name}/app.toml",
+		JSONPath:  ".service.replicas_min >= 1",
+		Headers:   map[string]string{"X-Team": "platform"},
+		DependsOn: []string{"c"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &fsdtos.Task{
+		ID:        "replicas-min",
+		Type:      "extract",
+		Source:    "github",
+		Repo:      "legacy-monorepo",
+		FilePath:  "services/${Metadata.Name}/app.toml",
+		Rule:      "jsonpath",
+		JSONPath:  ".service.replicas_min >= 1",
+		Headers:   map[string]string{"Accept": "application/json", "X-Team": "platform"},
+		DependsOn: []string{"c"},
+	}, overridden)
+
+	// The fact of the metric is left untouched
+	assert.Equal(t, "app.toml", fact.FilePath)
+	assert.Equal(t, "core", fact.Headers["X-Team"])
+
+	unchanged, err := ApplyFactOverride(fact, nil)
+	assert.NoError(t, err)
+	assert.Same(t, fact, unchanged)
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/motain/of-catalog/internal/modules/component/dtos"
+	metricdtos "github.com/motain/of-catalog/internal/modules/metric/dtos"
+)
+
+// MatchesMetric reports whether the metric is bound to the component: the type of the component must be
+// listed in the componentType of the metric, when set, and the component must match the selector, when set.
+// Metrics declaring neither are not bound to any component.
+func MatchesMetric(metric *metricdtos.MetricDTO, component *dtos.ComponentDTO) bool {
+	componentTypes := metric.Metadata.ComponentType
+	selector := metric.Metadata.Selector
+	if len(componentTypes) == 0 && selector == nil {
+		return false
+	}
+
+	if len(componentTypes) > 0 && !slices.Contains(componentTypes, component.Metadata.ComponentType) {
+		return false
+	}
+
+	return selector == nil || MatchesSelector(selector, component)
+}
+
+// MatchesSelector reports whether the component matches every criteria of the selector.
+func MatchesSelector(selector *metricdtos.MetricSelectorDTO, component *dtos.ComponentDTO) bool {
+	if len(selector.ComponentTypes) > 0 && !slices.Contains(selector.ComponentTypes, component.Metadata.ComponentType) {
+		return false
+	}
+
+	for _, label := range selector.Labels {
+		if !slices.Contains(component.Spec.Labels, label) {
+			return false
+		}
+	}
+
+	if len(selector.Tribes) > 0 && !slices.Contains(selector.Tribes, component.Spec.Tribe) {
+		return false
+	}
+
+	if len(selector.Squads) > 0 && !slices.Contains(selector.Squads, component.Spec.Squad) {
+		return false
+	}
+
+	for key, expected := range selector.Fields {
+		actual, found := component.Spec.Fields[key]
+		if !found || fmt.Sprint(actual) != fmt.Sprint(expected) {
+			return false
+		}
+	}
+
+	return len(selector.Names) == 0 || slices.ContainsFunc(selector.Names, func(pattern string) bool {
+		matched, _ := path.Match(pattern, component.Metadata.Name)
+		return matched
+	})
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/motain/of-catalog/internal/modules/component/dtos"
+	metricdtos "github.com/motain/of-catalog/internal/modules/metric/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesMetric(t *testing.T) {
+	component := &dtos.ComponentDTO{
+		Metadata: dtos.Metadata{Name: "payments-api", ComponentType: "service"},
+		Spec: dtos.Spec{
+			Labels: []string{"pii", "tier-1"},
+			Tribe:  "payments",
+			Squad:  "checkout",
+			Fields: map[string]interface{}{"usesKafka": true, "tier": 1},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		componentTypes []string
+		selector       *metricdtos.MetricSelectorDTO
+		want           bool
+	}{
+		{name: "component type", componentTypes: []string{"service"}, want: true},
+		{name: "other component type", componentTypes: []string{"cloud-resource"}, want: false},
+		{name: "no component type nor selector", want: false},
+		{name: "empty selector", selector: &metricdtos.MetricSelectorDTO{}, want: true},
+		{
+			name:     "selector component types",
+			selector: &metricdtos.MetricSelectorDTO{ComponentTypes: []string{"website", "service"}},
+			want:     true,
+		},
+		{
+			name:     "labels",
+			selector: &metricdtos.MetricSelectorDTO{Labels: []string{"pii", "tier-1"}},
+			want:     true,
+		},
+		{
+			name:     "missing label",
+			selector: &metricdtos.MetricSelectorDTO{Labels: []string{"pii", "public"}},
+			want:     false,
+		},
+		{
+			name:     "tribe and squad",
+			selector: &metricdtos.MetricSelectorDTO{Tribes: []string{"payments"}, Squads: []string{"checkout", "wallet"}},
+			want:     true,
+		},
+		{
+			name:     "other squad",
+			selector: &metricdtos.MetricSelectorDTO{Squads: []string{"wallet"}},
+			want:     false,
+		},
+		{
+			name:     "fields",
+			selector: &metricdtos.MetricSelectorDTO{Fields: map[string]interface{}{"usesKafka": true, "tier": 1}},
+			want:     true,
+		},
+		{
+			name:     "other field value",
+			selector: &metricdtos.MetricSelectorDTO{Fields: map[string]interface{}{"usesKafka": false}},
+			want:     false,
+		},
+		{
+			name:     "missing field",
+			selector: &metricdtos.MetricSelectorDTO{Fields: map[string]interface{}{"usesRedis": true}},
+			want:     false,
+		},
+		{
+			name:     "name glob",
+			selector: &metricdtos.MetricSelectorDTO{Names: []string{"search-*", "payments-*"}},
+			want:     true,
+		},
+		{
+			name:     "other name glob",
+			selector: &metricdtos.MetricSelectorDTO{Names: []string{"search-*"}},
+			want:     false,
+		},
+		{
+			name:           "component type and selector",
+			componentTypes: []string{"cloud-resource"},
+			selector:       &metricdtos.MetricSelectorDTO{Labels: []string{"pii"}},
+			want:           false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := &metricdtos.MetricDTO{}
+			metric.Metadata.ComponentType = tt.componentTypes
+			metric.Metadata.Selector = tt.selector
+
+			assert.Equal(t, tt.want, MatchesMetric(metric, component))
+		})
+	}
+}
@@ -1,54 +1,348 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/motain/of-catalog/internal/modules/component/dtos"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"gopkg.in/yaml.v3"
 )
 
-func ReplaceMetricFactPlaceholders(placeholder string, component dtos.ComponentDTO) string {
-	re := regexp.MustCompile(`\$\{(.*?)\}`)
-	replaceFromComponent := func(path string) string {
-		value, err := getFieldByPath(component, path)
+// ReplaceMetricFactPlaceholders replaces the ${...} placeholders of text with values of the component.
+//
+// A placeholder is a path in the component definition followed by optional functions:
+//
+//	${Metadata.Name}                        fields of structs
+//	${Spec.Fields.tier}                     keys of maps, ${Spec.Fields["my.key"]} for keys with dots
+//	${Spec.Links[0].URL}                    items of lists
+//	${Spec.Fields.tier | default "3"}       value used when the path does not exist or is empty
+//	${Metadata.Name | lower | replace "-" "_" | trimPrefix "of_"}
+//
+// Unknown paths without default and paths not leading to a scalar are errors. $${ is replaced with a
+// literal ${, e.g. for shell variables or JSON path expressions.
+func ReplaceMetricFactPlaceholders(text string, component dtos.ComponentDTO) (string, error) {
+	var replaced strings.Builder
+	for {
+		start := strings.Index(text, "${")
+		if start == -1 {
+			replaced.WriteString(text)
+			return replaced.String(), nil
+		}
+
+		if start > 0 && text[start-1] == '$' {
+			replaced.WriteString(text[:start-1])
+			replaced.WriteString("${")
+			text = text[start+2:]
+			continue
+		}
+
+		end := placeholderEnd(text, start+2)
+		if end == -1 {
+			return "", fmt.Errorf("unterminated placeholder %q", text[start:])
+		}
+
+		value, renderErr := renderPlaceholder(text[start+2:end], component)
+		if renderErr != nil {
+			return "", fmt.Errorf("placeholder %s: %v", text[start:end+1], renderErr)
+		}
+
+		replaced.WriteString(text[:start])
+		replaced.WriteString(value)
+		text = text[end+1:]
+	}
+}
+
+// ReplaceFactPlaceholders returns a copy of the fact with the placeholders of every string property
+// (including lists, maps and nested properties such as auth) replaced. The exec plugin and the inline
+// schema are not templates and are left as is, like the fact definition shared across components.
+func ReplaceFactPlaceholders(fact *fsdtos.Task, component dtos.ComponentDTO) (*fsdtos.Task, error) {
+	content, marshalErr := yaml.Marshal(fact)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+	// the document holds the mapping of the properties of the fact, keys and values alternating
+	properties := node.Content[0].Content
+	for i := 0; i+1 < len(properties); i += 2 {
+		if untemplatedProperties[properties[i].Value] {
+			continue
+		}
+		if err := replaceNodePlaceholders(properties[i+1], component); err != nil {
+			return nil, err
+		}
+	}
+
+	var replaced fsdtos.Task
+	if err := node.Decode(&replaced); err != nil {
+		return nil, err
+	}
+	return &replaced, nil
+}
+
+// untemplatedProperties are the properties of the facts where ${ is not a placeholder: the exec plugin
+// (shell variables) and the inline JSON schema (patterns).
+var untemplatedProperties = map[string]bool{"exec": true, "schema": true}
+
+func replaceNodePlaceholders(node *yaml.Node, component dtos.ComponentDTO) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		value, err := ReplaceMetricFactPlaceholders(node.Value, component)
 		if err != nil {
-			return ""
+			return err
 		}
+		node.Value = value
+	}
 
-		return fmt.Sprintf("%v", value)
+	for _, child := range node.Content {
+		if err := replaceNodePlaceholders(child, component); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return re.ReplaceAllStringFunc(placeholder, func(match string) string {
-		path := re.FindStringSubmatch(match)[1]
-		return replaceFromComponent(path)
-	})
+// placeholderEnd returns the index of the brace closing the placeholder starting at start,
+// braces within quoted arguments are ignored.
+func placeholderEnd(text string, start int) int {
+	quoted := false
+	for i := start; i < len(text); i++ {
+		switch {
+		case quoted && text[i] == '\\':
+			i++
+		case text[i] == '"':
+			quoted = !quoted
+		case !quoted && text[i] == '}':
+			return i
+		}
+	}
+	return -1
 }
 
-// getFieldByPath fetches a nested field value using dot notation
-func getFieldByPath(obj interface{}, path string) (interface{}, error) {
-	fields := strings.Split(path, ".")
-	val := reflect.ValueOf(obj)
+func renderPlaceholder(expression string, component dtos.ComponentDTO) (string, error) {
+	stages, parseErr := parsePipeline(expression)
+	if parseErr != nil {
+		return "", parseErr
+	}
 
-	// Traverse fields
-	for _, field := range fields {
-		// Dereference pointer if necessary
-		if val.Kind() == reflect.Ptr {
-			val = val.Elem()
+	path := stages[0]
+	if len(path) != 1 {
+		return "", errors.New("expected a path")
+	}
+
+	var value string
+	field, lookupErr := getFieldByPath(component, path[0])
+	if lookupErr == nil {
+		value, lookupErr = scalar(field)
+	}
+
+	for _, stage := range stages[1:] {
+		name, args := stage[0], stage[1:]
+		if name == "default" {
+			if len(args) != 1 {
+				return "", errors.New("default expects 1 argument")
+			}
+			if lookupErr != nil || value == "" {
+				value, lookupErr = args[0], nil
+			}
+			continue
 		}
 
-		// Ensure it's a struct
-		if val.Kind() != reflect.Struct {
-			return nil, fmt.Errorf("invalid path: %s", path)
+		if lookupErr != nil {
+			continue
+		}
+
+		var fnErr error
+		if value, fnErr = applyFunction(name, args, value); fnErr != nil {
+			return "", fnErr
 		}
+	}
 
-		// Get field by name
-		val = val.FieldByName(field)
+	return value, lookupErr
+}
+
+func applyFunction(name string, args []string, value string) (string, error) {
+	expectArgs := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("%s expects %d arguments, got %d", name, n, len(args))
+		}
+		return nil
+	}
 
-		// If field is invalid, return error
-		if !val.IsValid() {
-			return nil, fmt.Errorf("field not found: %s", field)
+	switch name {
+	case "lower":
+		return strings.ToLower(value), expectArgs(0)
+	case "replace":
+		if err := expectArgs(2); err != nil {
+			return "", err
+		}
+		return strings.ReplaceAll(value, args[0], args[1]), nil
+	case "trimPrefix":
+		if err := expectArgs(1); err != nil {
+			return "", err
+		}
+		return strings.TrimPrefix(value, args[0]), nil
+	default:
+		return "", fmt.Errorf("unknown function %s", name)
+	}
+}
+
+// parsePipeline splits a placeholder into stages separated by |, every stage being a list of words.
+// Words are bare (paths, function names) or double quoted strings.
+func parsePipeline(expression string) ([][]string, error) {
+	stages := [][]string{{}}
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '|':
+			stages = append(stages, []string{})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expression) && expression[end] != '"' {
+				if expression[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expression) {
+				return nil, errors.New("unterminated string")
+			}
+			word, unquoteErr := strconv.Unquote(expression[i : end+1])
+			if unquoteErr != nil {
+				return nil, fmt.Errorf("invalid string %s", expression[i:end+1])
+			}
+			stages[len(stages)-1] = append(stages[len(stages)-1], word)
+			i = end + 1
+		default:
+			end := i
+			for end < len(expression) && !strings.ContainsRune(" \t|", rune(expression[end])) {
+				if expression[end] == '"' {
+					// quoted map keys, e.g. Fields["my.key"]
+					closing := strings.IndexByte(expression[end+1:], '"')
+					if closing == -1 {
+						return nil, errors.New("unterminated string")
+					}
+					end += closing + 1
+				}
+				end++
+			}
+			stages[len(stages)-1] = append(stages[len(stages)-1], expression[i:end])
+			i = end
+		}
+	}
+
+	for _, stage := range stages {
+		if len(stage) == 0 {
+			return nil, errors.New("empty expression")
+		}
+	}
+	return stages, nil
+}
+
+// scalar formats values of the component, only scalars can replace placeholders.
+func scalar(field interface{}) (string, error) {
+	switch v := field.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprintf("%v", v), nil
+	default:
+		return "", fmt.Errorf("%T is not a scalar", field)
+	}
+}
+
+// pathElement is a field, a map key or a list index of a path.
+type pathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func parsePath(path string) ([]pathElement, error) {
+	var elements []pathElement
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path: %s", path)
+			}
+			inner := path[i+1 : i+end]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				elements = append(elements, pathElement{key: unquoted})
+			} else if index, err := strconv.Atoi(inner); err == nil {
+				elements = append(elements, pathElement{index: index, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("invalid path: %s", path)
+			}
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end == -1 {
+				end = len(path) - i
+			}
+			elements = append(elements, pathElement{key: path[i : i+end]})
+			i += end
+		}
+	}
+
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("invalid path: %s", path)
+	}
+	return elements, nil
+}
+
+// getFieldByPath fetches a nested value using dot notation: fields of structs, keys of maps
+// (Fields.tier or Fields["my.key"]) and items of lists (Links[0]).
+func getFieldByPath(obj interface{}, path string) (interface{}, error) {
+	elements, parseErr := parsePath(path)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	val := reflect.ValueOf(obj)
+	for _, element := range elements {
+		for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+			if val.IsNil() {
+				return nil, fmt.Errorf("field not found: %s", element.key)
+			}
+			val = val.Elem()
+		}
+
+		switch {
+		case element.isIndex:
+			if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+				return nil, fmt.Errorf("invalid path: %s", path)
+			}
+			if element.index < 0 || element.index >= val.Len() {
+				return nil, fmt.Errorf("index out of range: %d", element.index)
+			}
+			val = val.Index(element.index)
+		case val.Kind() == reflect.Struct:
+			val = val.FieldByName(element.key)
+			if !val.IsValid() {
+				return nil, fmt.Errorf("field not found: %s", element.key)
+			}
+		case val.Kind() == reflect.Map && val.Type().Key().Kind() == reflect.String:
+			val = val.MapIndex(reflect.ValueOf(element.key).Convert(val.Type().Key()))
+			if !val.IsValid() {
+				return nil, fmt.Errorf("key not found: %s", element.key)
+			}
+		default:
+			return nil, fmt.Errorf("invalid path: %s", path)
 		}
 	}
 
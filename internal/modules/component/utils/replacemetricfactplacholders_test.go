@@ -1,9 +1,11 @@
 package utils
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/motain/of-catalog/internal/modules/component/dtos"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
 )
 
 func TestGetFieldByPath(t *testing.T) {
@@ -52,6 +54,34 @@ func TestGetFieldByPath(t *testing.T) {
 			want:      nil,
 			expectErr: true,
 		},
+		{
+			name:      "map key",
+			obj:       map[string]interface{}{"tier": 1},
+			path:      "tier",
+			want:      1,
+			expectErr: false,
+		},
+		{
+			name:      "quoted map key",
+			obj:       map[string]interface{}{"my.key": "value"},
+			path:      `["my.key"]`,
+			want:      "value",
+			expectErr: false,
+		},
+		{
+			name:      "list index",
+			obj:       map[string]interface{}{"items": []string{"a", "b"}},
+			path:      "items[1]",
+			want:      "b",
+			expectErr: false,
+		},
+		{
+			name:      "list index out of range",
+			obj:       map[string]interface{}{"items": []string{"a"}},
+			path:      "items[1]",
+			want:      nil,
+			expectErr: true,
+		},
 		{
 			name: "invalid nested path",
 			obj: TestStruct{
@@ -83,6 +113,7 @@ func TestReplaceMetricFactPlaceholders(t *testing.T) {
 		name        string
 		placeholder string
 		want        string
+		expectErr   bool
 	}{
 		{
 			name:        "simple placeholder",
@@ -99,29 +130,143 @@ func TestReplaceMetricFactPlaceholders(t *testing.T) {
 			placeholder: "Simple: ${Metadata.Name}, Nested: ${Spec.Name}",
 			want:        "Simple: simpleValue, Nested: simpleValue",
 		},
+		{
+			name:        "map key",
+			placeholder: "tier-${Spec.Fields.tier}",
+			want:        "tier-1",
+		},
+		{
+			name:        "quoted map key",
+			placeholder: `${Spec.Fields["team.slack"]}`,
+			want:        "#team",
+		},
+		{
+			name:        "list index",
+			placeholder: "${Spec.Links[0].URL}",
+			want:        "https://example.com",
+		},
+		{
+			name:        "default for a missing key",
+			placeholder: `${Spec.Fields.missing | default "none"}`,
+			want:        "none",
+		},
+		{
+			name:        "default for an empty value",
+			placeholder: `${Spec.Description | default "none"}`,
+			want:        "none",
+		},
+		{
+			name:        "default ignored for a value",
+			placeholder: `${Spec.Name | default "none"}`,
+			want:        "simpleValue",
+		},
+		{
+			name:        "functions",
+			placeholder: `${Spec.Fields.slug | lower | replace "-" "_" | trimPrefix "of_"}`,
+			want:        "my_service",
+		},
+		{
+			name:        "no placeholder",
+			placeholder: "Value: $HOME {{item}}",
+			want:        "Value: $HOME {{item}}",
+		},
+		{
+			name:        "escaped placeholder",
+			placeholder: "echo $${HOME}/${Spec.Name}",
+			want:        "echo ${HOME}/simpleValue",
+		},
 		{
 			name:        "invalid placeholder",
 			placeholder: "Value: ${Meta}",
-			want:        "Value: ",
+			expectErr:   true,
 		},
 		{
 			name:        "invalid nested placeholder",
 			placeholder: "Value: ${Meta.InvalidField}",
-			want:        "Value: ",
+			expectErr:   true,
+		},
+		{
+			name:        "not a scalar",
+			placeholder: "${Spec.Links}",
+			expectErr:   true,
+		},
+		{
+			name:        "unknown function",
+			placeholder: "${Spec.Name | upper}",
+			expectErr:   true,
+		},
+		{
+			name:        "unterminated placeholder",
+			placeholder: "${Spec.Name",
+			expectErr:   true,
 		},
 	}
 
 	comp := dtos.ComponentDTO{
 		Metadata: dtos.Metadata{Name: "simpleValue"},
-		Spec:     dtos.Spec{Name: "simpleValue"},
+		Spec: dtos.Spec{
+			Name:   "simpleValue",
+			Fields: map[string]interface{}{"tier": 1, "team.slack": "#team", "slug": "OF-My-Service"},
+			Links:  []dtos.Link{{URL: "https://example.com"}},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := ReplaceMetricFactPlaceholders(tt.placeholder, comp)
+			got, err := ReplaceMetricFactPlaceholders(tt.placeholder, comp)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ReplaceMetricFactPlaceholders() error = %v, expectErr %v", err, tt.expectErr)
+				return
+			}
 			if got != tt.want {
 				t.Errorf("ReplaceMetricFactPlaceholders() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestReplaceFactPlaceholders(t *testing.T) {
+	comp := dtos.ComponentDTO{
+		Metadata: dtos.Metadata{Name: "my-service"},
+		Spec:     dtos.Spec{Fields: map[string]interface{}{"token": "MY_TOKEN"}},
+	}
+
+	fact := &fsdtos.Task{
+		ID:           "fact",
+		FilePath:     "services/${Metadata.Name}/app.toml",
+		JSONPath:     ".services[\"${Metadata.Name}\"]",
+		SearchString: "${Metadata.Name | replace \"-\" \"_\"}",
+		Headers:      map[string]string{"service": "${Metadata.Name}"},
+		Auth:         &fsdtos.TaskAuth{TokenVar: "${Spec.Fields.token}"},
+		Exec:         &fsdtos.TaskExec{Command: "sh", Args: []string{"-c", "ls ${HOME}"}},
+		Schema:       map[string]interface{}{"pattern": "^${[a-z]+}$"},
+	}
+
+	got, err := ReplaceFactPlaceholders(fact, comp)
+	if err != nil {
+		t.Fatalf("ReplaceFactPlaceholders() error = %v", err)
+	}
+
+	want := &fsdtos.Task{
+		ID:           "fact",
+		FilePath:     "services/my-service/app.toml",
+		JSONPath:     ".services[\"my-service\"]",
+		SearchString: "my_service",
+		Headers:      map[string]string{"service": "my-service"},
+		Auth:         &fsdtos.TaskAuth{TokenVar: "MY_TOKEN"},
+		Exec:         &fsdtos.TaskExec{Command: "sh", Args: []string{"-c", "ls ${HOME}"}},
+		Schema:       map[string]interface{}{"pattern": "^${[a-z]+}$"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReplaceFactPlaceholders() = %+v, want %+v", got, want)
+	}
+
+	if fact.FilePath != "services/${Metadata.Name}/app.toml" || fact.Headers["service"] != "${Metadata.Name}" {
+		t.Errorf("ReplaceFactPlaceholders() modified the fact")
+	}
+
+	fact.URI = "${Spec.Fields.missing}"
+	if _, err := ReplaceFactPlaceholders(fact, comp); err == nil {
+		t.Errorf("ReplaceFactPlaceholders() expected an error for an unknown path")
+	}
+}
@@ -1,6 +1,7 @@
 package dtos
 
 import (
+	"fmt"
 	"reflect"
 
 	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
@@ -11,10 +12,11 @@ type MetricDTO struct {
 	APIVersion string `yaml:"apiVersion"`
 	Kind       string `yaml:"kind"`
 	Metadata   struct {
-		Name          string            `yaml:"name"`
-		Labels        map[string]string `yaml:"labels"`
-		ComponentType []string          `yaml:"componentType"`
-		Facts         []*fsdtos.Task    `yaml:"facts"`
+		Name          string             `yaml:"name"`
+		Labels        map[string]string  `yaml:"labels"`
+		ComponentType []string           `yaml:"componentType"`
+		Selector      *MetricSelectorDTO `yaml:"selector,omitempty"` // Components must also match the selector, when set
+		Facts         []*fsdtos.Task     `yaml:"facts"`
 	} `yaml:"metadata"`
 	Spec MetricSpec `yaml:"spec"`
 }
@@ -23,6 +25,40 @@ func GetMetricUniqueKey(m *MetricDTO) string {
 	return m.Spec.Name
 }
 
+// ValidateMetric checks the facts of a metric definition, with the fact templates it references expanded.
+func ValidateMetric(m *MetricDTO, templates map[string]*FactTemplateDTO) error {
+	if m.Metadata.Selector != nil {
+		if err := m.Metadata.Selector.Validate(); err != nil {
+			return fmt.Errorf("metric %s: %v", m.Metadata.Name, err)
+		}
+	}
+
+	facts, expandErr := ExpandFactTemplates(m.Metadata.Facts, templates)
+	if expandErr != nil {
+		return fmt.Errorf("metric %s: %v", m.Metadata.Name, expandErr)
+	}
+
+	for _, fact := range facts {
+		if err := fact.Validate(); err != nil {
+			return fmt.Errorf("metric %s, fact %s: %v", m.Metadata.Name, fact.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// HasEqualFacts reports whether two definitions of a metric have the same facts, with the fact templates
+// of each definition expanded. Definitions whose templates cannot be expanded are different.
+func HasEqualFacts(m1, m2 *MetricDTO, templates1, templates2 map[string]*FactTemplateDTO) bool {
+	facts1, expandErr1 := ExpandFactTemplates(m1.Metadata.Facts, templates1)
+	facts2, expandErr2 := ExpandFactTemplates(m2.Metadata.Facts, templates2)
+	if expandErr1 != nil || expandErr2 != nil {
+		return false
+	}
+
+	return isEqualFacts(facts1, facts2)
+}
+
 func FromStateToConfig(state *MetricDTO, conf *MetricDTO) {
 	conf.Spec.ID = state.Spec.ID
 }
@@ -34,6 +70,7 @@ func IsEqualMetric(m1, m2 *MetricDTO) bool {
 		m1.Metadata.Name == m2.Metadata.Name &&
 		isEqualLabels(m1.Metadata.Labels, m2.Metadata.Labels) &&
 		isEqualComponentTypes(m1.Metadata.ComponentType, m2.Metadata.ComponentType) &&
+		isEqualSelectors(m1.Metadata.Selector, m2.Metadata.Selector) &&
 		isEqualFacts(m1.Metadata.Facts, m2.Metadata.Facts)
 }
 
@@ -0,0 +1,272 @@
+package dtos
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"gopkg.in/yaml.v3"
+)
+
+// templateParams matches the ${params.name} placeholders of fact templates.
+var templateParams = regexp.MustCompile(`\$\{params\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// FactTemplateDTO is a data transfer object representing a set of facts shared by several metrics.
+// Metrics reference a template from a fact declaring `template: name` or `template: name@version`
+// and the parameters of the template in `params`. References are expanded when metrics are bound.
+type FactTemplateDTO struct {
+	APIVersion string `yaml:"apiVersion"`
+	Kind       string `yaml:"kind"`
+	Metadata   struct {
+		Name    string `yaml:"name"`
+		Version int    `yaml:"version,omitempty"` // Defaults to 1
+	} `yaml:"metadata"`
+	Spec FactTemplateSpec `yaml:"spec"`
+}
+
+type FactTemplateSpec struct {
+	Description string                  `yaml:"description,omitempty"`
+	Parameters  []FactTemplateParameter `yaml:"parameters,omitempty"`
+	Facts       []*fsdtos.Task          `yaml:"facts"`
+}
+
+// FactTemplateParameter declares a parameter of a template, parameters without default are required.
+type FactTemplateParameter struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description,omitempty"`
+	Default     *string `yaml:"default,omitempty"`
+}
+
+func (t *FactTemplateDTO) version() int {
+	if t.Metadata.Version == 0 {
+		return 1
+	}
+	return t.Metadata.Version
+}
+
+func GetFactTemplateUniqueKey(t *FactTemplateDTO) string {
+	return fmt.Sprintf("%s@%d", t.Metadata.Name, t.version())
+}
+
+// FindFactTemplate returns the template referenced by ref, name@version or name for the latest version.
+func FindFactTemplate(templates map[string]*FactTemplateDTO, ref string) (*FactTemplateDTO, error) {
+	if strings.Contains(ref, "@") {
+		template, found := templates[ref]
+		if !found {
+			return nil, fmt.Errorf("unknown fact template %s", ref)
+		}
+		return template, nil
+	}
+
+	var latest *FactTemplateDTO
+	for _, template := range templates {
+		if template.Metadata.Name == ref && (latest == nil || template.version() > latest.version()) {
+			latest = template
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("unknown fact template %s", ref)
+	}
+	return latest, nil
+}
+
+// ValidateFactTemplate checks that the facts of a template only use declared parameters
+// and do not reference other templates.
+func ValidateFactTemplate(t *FactTemplateDTO) error {
+	declared := make(map[string]bool, len(t.Spec.Parameters))
+	for _, parameter := range t.Spec.Parameters {
+		declared[parameter.Name] = true
+	}
+
+	if len(t.Spec.Facts) == 0 {
+		return fmt.Errorf("fact template %s has no facts", GetFactTemplateUniqueKey(t))
+	}
+
+	for _, fact := range t.Spec.Facts {
+		if fact.Template != "" {
+			return fmt.Errorf("fact template %s, fact %s: fact templates cannot reference other templates", GetFactTemplateUniqueKey(t), fact.ID)
+		}
+	}
+
+	content, marshalErr := yaml.Marshal(t.Spec.Facts)
+	if marshalErr != nil {
+		return marshalErr
+	}
+	for _, match := range templateParams.FindAllStringSubmatch(string(content), -1) {
+		if !declared[match[1]] {
+			return fmt.Errorf("fact template %s references undeclared parameter %s", GetFactTemplateUniqueKey(t), match[1])
+		}
+	}
+
+	return nil
+}
+
+// ExpandFactTemplates replaces the facts referencing a template with the facts of the template.
+// The ids of the template facts are prefixed with the id of the reference (e.g. replicas.read-file),
+// except for the last fact which takes the id of the reference so that other facts can depend on it.
+func ExpandFactTemplates(facts []*fsdtos.Task, templates map[string]*FactTemplateDTO) ([]*fsdtos.Task, error) {
+	expanded := make([]*fsdtos.Task, 0, len(facts))
+	for _, fact := range facts {
+		if fact == nil || fact.Template == "" {
+			expanded = append(expanded, fact)
+			continue
+		}
+
+		templateFacts, expandErr := expandFactTemplate(fact, templates)
+		if expandErr != nil {
+			return nil, fmt.Errorf("fact %s: %v", fact.ID, expandErr)
+		}
+		expanded = append(expanded, templateFacts...)
+	}
+
+	return expanded, nil
+}
+
+func expandFactTemplate(ref *fsdtos.Task, templates map[string]*FactTemplateDTO) ([]*fsdtos.Task, error) {
+	if err := validateFactTemplateReference(ref); err != nil {
+		return nil, err
+	}
+
+	template, findErr := FindFactTemplate(templates, ref.Template)
+	if findErr != nil {
+		return nil, findErr
+	}
+
+	params, paramsErr := templateParameters(template, ref.Params)
+	if paramsErr != nil {
+		return nil, fmt.Errorf("fact template %s: %v", GetFactTemplateUniqueKey(template), paramsErr)
+	}
+
+	facts, renderErr := renderTemplateFacts(template.Spec.Facts, params)
+	if renderErr != nil {
+		return nil, fmt.Errorf("fact template %s: %v", GetFactTemplateUniqueKey(template), renderErr)
+	}
+
+	ids := make(map[string]string, len(facts))
+	for i, fact := range facts {
+		if i == len(facts)-1 {
+			ids[fact.ID] = ref.ID
+		} else {
+			ids[fact.ID] = ref.ID + "." + fact.ID
+		}
+	}
+
+	for i, fact := range facts {
+		isRoot := len(fact.DependsOn) == 0
+		renameFactReferences(fact, ids)
+		if i == len(facts)-1 && ref.Name != "" {
+			fact.Name = ref.Name
+		}
+		// the dependencies of the reference are the dependencies of the facts of the template depending on no other fact
+		if isRoot {
+			for _, dependsOn := range ref.DependsOn {
+				if !slices.Contains(fact.DependsOn, dependsOn) {
+					fact.DependsOn = append(fact.DependsOn, dependsOn)
+				}
+			}
+		}
+	}
+
+	return facts, nil
+}
+
+// validateFactTemplateReference checks that a reference only sets id, name, template, params and dependsOn,
+// the other properties of the facts are defined by the template.
+func validateFactTemplateReference(ref *fsdtos.Task) error {
+	rest := *ref
+	rest.ID, rest.Name, rest.Template, rest.Params, rest.DependsOn = "", "", "", nil, nil
+	if !reflect.DeepEqual(rest, fsdtos.Task{}) {
+		return errors.New("fact template references only support id, name, template, params and dependsOn")
+	}
+	return nil
+}
+
+// templateParameters returns the values of the parameters of the template, applying defaults.
+func templateParameters(template *FactTemplateDTO, values map[string]string) (map[string]string, error) {
+	params := make(map[string]string, len(template.Spec.Parameters))
+	for _, parameter := range template.Spec.Parameters {
+		value, found := values[parameter.Name]
+		switch {
+		case found:
+			params[parameter.Name] = value
+		case parameter.Default != nil:
+			params[parameter.Name] = *parameter.Default
+		default:
+			return nil, fmt.Errorf("missing parameter %s", parameter.Name)
+		}
+	}
+
+	for name := range values {
+		if _, declared := params[name]; !declared {
+			return nil, fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+
+	return params, nil
+}
+
+// renderTemplateFacts returns a copy of the facts with the parameters replaced in every string value.
+func renderTemplateFacts(facts []*fsdtos.Task, params map[string]string) ([]*fsdtos.Task, error) {
+	content, marshalErr := yaml.Marshal(facts)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(content, &node); err != nil {
+		return nil, err
+	}
+	replaceParams(&node, params)
+
+	var rendered []*fsdtos.Task
+	if err := node.Decode(&rendered); err != nil {
+		return nil, fmt.Errorf("invalid facts: %v", err)
+	}
+	return rendered, nil
+}
+
+func replaceParams(node *yaml.Node, params map[string]string) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		node.Value = templateParams.ReplaceAllStringFunc(node.Value, func(match string) string {
+			return params[templateParams.FindStringSubmatch(match)[1]]
+		})
+	}
+
+	for _, child := range node.Content {
+		replaceParams(child, params)
+	}
+}
+
+// renameFactReferences renames the template facts a fact depends on.
+func renameFactReferences(fact *fsdtos.Task, ids map[string]string) {
+	if id, found := ids[fact.ID]; found {
+		fact.ID = id
+	}
+
+	for i, dependsOn := range fact.DependsOn {
+		id, found := ids[dependsOn]
+		if !found {
+			continue
+		}
+		fact.DependsOn[i] = id
+
+		if weight, weighted := fact.Weights[dependsOn]; weighted {
+			delete(fact.Weights, dependsOn)
+			fact.Weights[id] = weight
+		}
+		if fact.Expression != "" {
+			variable := regexp.MustCompile(`\b` + regexp.QuoteMeta(fsdtos.ExpressionVariable(dependsOn)) + `\b`)
+			fact.Expression = variable.ReplaceAllString(fact.Expression, fsdtos.ExpressionVariable(id))
+		}
+	}
+
+	if fact.ForEach != nil {
+		if id, found := ids[fact.ForEach.Dependency]; found {
+			fact.ForEach.Dependency = id
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/motain/of-catalog/internal/modules/metric/dtos"
+	fsdtos "github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func factTemplate(name string, version int, parameters []dtos.FactTemplateParameter, facts ...*fsdtos.Task) *dtos.FactTemplateDTO {
+	template := &dtos.FactTemplateDTO{Kind: "FactTemplate"}
+	template.Metadata.Name = name
+	template.Metadata.Version = version
+	template.Spec.Parameters = parameters
+	template.Spec.Facts = facts
+	return template
+}
+
+func TestExpandFactTemplates(t *testing.T) {
+	defaultRepo := "${Metadata.Name}"
+	templates := map[string]*dtos.FactTemplateDTO{}
+	for _, template := range []*dtos.FactTemplateDTO{
+		factTemplate("app-toml", 0,
+			[]dtos.FactTemplateParameter{{Name: "jsonPath"}, {Name: "repo", Default: &defaultRepo}},
+			&fsdtos.Task{ID: "read", Type: "extract", Source: "github", Repo: "${params.repo}", FilePath: "app.toml", Rule: "jsonpath", JSONPath: "${params.jsonPath}"},
+		),
+		factTemplate("app-toml", 2,
+			[]dtos.FactTemplateParameter{{Name: "jsonPath"}},
+			&fsdtos.Task{ID: "read", Type: "extract", Source: "local", FilePath: "app.toml", Rule: "jsonpath", JSONPath: "${params.jsonPath}"},
+			&fsdtos.Task{ID: "total", Type: "aggregate", DependsOn: []string{"read"}, Method: "expression", Expression: "read * 2", Weights: map[string]float64{"read": 1}},
+		),
+	} {
+		templates[dtos.GetFactTemplateUniqueKey(template)] = template
+	}
+
+	tests := []struct {
+		name        string
+		facts       []*fsdtos.Task
+		expected    []*fsdtos.Task
+		expectedErr string
+	}{
+		{
+			name: "version with defaults",
+			facts: []*fsdtos.Task{
+				{ID: "replicas", Name: "Replicas", Template: "app-toml@1", Params: map[string]string{"jsonPath": ".service.replicas_min"}},
+				{ID: "check", Type: "validate", DependsOn: []string{"replicas"}, Rule: "unique"},
+			},
+			expected: []*fsdtos.Task{
+				{ID: "replicas", Name: "Replicas", Type: "extract", Source: "github", Repo: "${Metadata.Name}", FilePath: "app.toml", Rule: "jsonpath", JSONPath: ".service.replicas_min"},
+				{ID: "check", Type: "validate", DependsOn: []string{"replicas"}, Rule: "unique"},
+			},
+		},
+		{
+			name:  "latest version with several facts",
+			facts: []*fsdtos.Task{{ID: "replicas", Template: "app-toml", Params: map[string]string{"jsonPath": ".replicas"}}},
+			expected: []*fsdtos.Task{
+				{ID: "replicas.read", Type: "extract", Source: "local", FilePath: "app.toml", Rule: "jsonpath", JSONPath: ".replicas"},
+				{
+					ID: "replicas", Type: "aggregate", DependsOn: []string{"replicas.read"}, Method: "expression",
+					Expression: "replicas_read * 2", Weights: map[string]float64{"replicas.read": 1},
+				},
+			},
+		},
+		{
+			name: "reference dependencies",
+			facts: []*fsdtos.Task{
+				{ID: "repos", Type: "extract", Source: "github"},
+				{ID: "replicas", Template: "app-toml@2", Params: map[string]string{"jsonPath": ".replicas"}, DependsOn: []string{"repos"}},
+			},
+			expected: []*fsdtos.Task{
+				{ID: "repos", Type: "extract", Source: "github"},
+				{ID: "replicas.read", Type: "extract", Source: "local", FilePath: "app.toml", Rule: "jsonpath", JSONPath: ".replicas", DependsOn: []string{"repos"}},
+				{
+					ID: "replicas", Type: "aggregate", DependsOn: []string{"replicas.read"}, Method: "expression",
+					Expression: "replicas_read * 2", Weights: map[string]float64{"replicas.read": 1},
+				},
+			},
+		},
+		{
+			name:        "reference with fact properties",
+			facts:       []*fsdtos.Task{{ID: "replicas", Template: "app-toml@2", Params: map[string]string{"jsonPath": "."}, Rule: "unique"}},
+			expectedErr: "fact replicas: fact template references only support id, name, template, params and dependsOn",
+		},
+		{
+			name:        "unknown template",
+			facts:       []*fsdtos.Task{{ID: "replicas", Template: "app-toml@3"}},
+			expectedErr: "fact replicas: unknown fact template app-toml@3",
+		},
+		{
+			name:        "missing parameter",
+			facts:       []*fsdtos.Task{{ID: "replicas", Template: "app-toml@1"}},
+			expectedErr: "fact replicas: fact template app-toml@1: missing parameter jsonPath",
+		},
+		{
+			name:        "unknown parameter",
+			facts:       []*fsdtos.Task{{ID: "replicas", Template: "app-toml@2", Params: map[string]string{"jsonPath": ".", "repo": "x"}}},
+			expectedErr: "fact replicas: fact template app-toml@2: unknown parameter repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facts, err := dtos.ExpandFactTemplates(tt.facts, templates)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, facts)
+		})
+	}
+
+	// Templates are not modified by expansions
+	assert.Equal(t, "${params.jsonPath}", templates["app-toml@1"].Spec.Facts[0].JSONPath)
+}
+
+func TestValidateFactTemplate(t *testing.T) {
+	template := factTemplate("app-toml", 1,
+		[]dtos.FactTemplateParameter{{Name: "jsonPath"}},
+		&fsdtos.Task{ID: "read", Type: "extract", Source: "github", Repo: "${params.repo}", JSONPath: "${params.jsonPath}"},
+	)
+	assert.EqualError(t, dtos.ValidateFactTemplate(template), "fact template app-toml@1 references undeclared parameter repo")
+
+	template.Spec.Facts = []*fsdtos.Task{{ID: "nested", Template: "other"}}
+	assert.EqualError(t, dtos.ValidateFactTemplate(template), "fact template app-toml@1, fact nested: fact templates cannot reference other templates")
+}
+
+func TestHasEqualFacts(t *testing.T) {
+	metric := func(facts ...*fsdtos.Task) *dtos.MetricDTO {
+		m := &dtos.MetricDTO{}
+		m.Metadata.Facts = facts
+		return m
+	}
+	templates := func(jsonPath string) map[string]*dtos.FactTemplateDTO {
+		template := factTemplate("app-toml", 1, nil, &fsdtos.Task{ID: "read", Type: "extract", Source: "local", FilePath: "app.toml", Rule: "jsonpath", JSONPath: jsonPath})
+		return map[string]*dtos.FactTemplateDTO{dtos.GetFactTemplateUniqueKey(template): template}
+	}
+	reference := &fsdtos.Task{ID: "replicas", Template: "app-toml@1"}
+
+	tests := []struct {
+		name       string
+		m1, m2     *dtos.MetricDTO
+		templates1 map[string]*dtos.FactTemplateDTO
+		templates2 map[string]*dtos.FactTemplateDTO
+		expected   bool
+	}{
+		{
+			name:     "same facts",
+			m1:       metric(&fsdtos.Task{ID: "a", Type: "extract", URI: "https://example.com"}),
+			m2:       metric(&fsdtos.Task{ID: "a", Type: "extract", URI: "https://example.com"}),
+			expected: true,
+		},
+		{
+			name:     "changed fact",
+			m1:       metric(&fsdtos.Task{ID: "a", Type: "extract", URI: "https://example.com"}),
+			m2:       metric(&fsdtos.Task{ID: "a", Type: "extract", URI: "https://example.org"}),
+			expected: false,
+		},
+		{
+			name:       "same template",
+			m1:         metric(reference),
+			m2:         metric(reference),
+			templates1: templates(".replicas"),
+			templates2: templates(".replicas"),
+			expected:   true,
+		},
+		{
+			name:       "changed template",
+			m1:         metric(reference),
+			m2:         metric(reference),
+			templates1: templates(".replicas"),
+			templates2: templates(".service.replicas"),
+			expected:   false,
+		},
+		{
+			name:       "missing template",
+			m1:         metric(reference),
+			m2:         metric(reference),
+			templates2: templates(".replicas"),
+			expected:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, dtos.HasEqualFacts(tt.m1, tt.m2, tt.templates1, tt.templates2))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package dtos
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+)
+
+// MetricSelectorDTO narrows the components a metric is bound to. Every criteria set must match:
+// a component matches a list when it matches any of its values, except labels which must all be
+// present. Fields are matched by value (e.g. `usesKafka: true`) and names are glob patterns.
+type MetricSelectorDTO struct {
+	ComponentTypes []string               `yaml:"componentTypes,omitempty"`
+	Labels         []string               `yaml:"labels,omitempty"`
+	Tribes         []string               `yaml:"tribes,omitempty"`
+	Squads         []string               `yaml:"squads,omitempty"`
+	Fields         map[string]interface{} `yaml:"fields,omitempty"`
+	Names          []string               `yaml:"names,omitempty"`
+}
+
+func (s *MetricSelectorDTO) Validate() error {
+	for _, name := range s.Names {
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("invalid selector name pattern %q", name)
+		}
+	}
+
+	for key, value := range s.Fields {
+		switch value.(type) {
+		case string, bool, int, float64:
+		default:
+			return fmt.Errorf("selector field %s must be a string, number or boolean", key)
+		}
+	}
+
+	return nil
+}
+
+func isEqualSelectors(s1, s2 *MetricSelectorDTO) bool {
+	return reflect.DeepEqual(s1, s2)
+}
@@ -0,0 +1,42 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/motain/of-catalog/internal/modules/metric/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricSelectorDTO_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector dtos.MetricSelectorDTO
+		wantErr  string
+	}{
+		{
+			name:     "valid selector",
+			selector: dtos.MetricSelectorDTO{Names: []string{"payments-*"}, Fields: map[string]interface{}{"usesKafka": true, "tier": 1}},
+		},
+		{
+			name:     "invalid name pattern",
+			selector: dtos.MetricSelectorDTO{Names: []string{"payments-["}},
+			wantErr:  `invalid selector name pattern "payments-["`,
+		},
+		{
+			name:     "field not a scalar",
+			selector: dtos.MetricSelectorDTO{Fields: map[string]interface{}{"owners": []string{"a"}}},
+			wantErr:  "selector field owners must be a string, number or boolean",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.selector.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -2,8 +2,12 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"slices"
+	"strings"
 
+	componentdtos "github.com/motain/of-catalog/internal/modules/component/dtos"
 	"github.com/motain/of-catalog/internal/modules/metric/dtos"
 	"github.com/motain/of-catalog/internal/modules/metric/repository"
 	"github.com/motain/of-catalog/internal/modules/metric/resources"
@@ -35,6 +39,16 @@ func (h *ApplyHandler) Apply(ctx context.Context, configRootLocation string, sta
 	if errConfig != nil {
 		log.Fatalf("error: %v", errConfig)
 	}
+	stateFactTemplates, errTState := yaml.Parse(yaml.GetStateInput(stateRootLocation), dtos.GetFactTemplateUniqueKey)
+	if errTState != nil {
+		log.Fatalf("error: %v", errTState)
+	}
+	factTemplates := h.applyFactTemplates(parseInput)
+	for _, metric := range configMetrics {
+		if validationErr := dtos.ValidateMetric(metric, factTemplates); validationErr != nil {
+			log.Fatalf("error: %v", validationErr)
+		}
+	}
 	// jsonData, err := json.MarshalIndent(configMetrics, "", "  ")
 	// if err != nil {
 	// 	log.Fatalf("error converting configMetrics to JSON: %v", err)
@@ -58,6 +72,77 @@ func (h *ApplyHandler) Apply(ctx context.Context, configRootLocation string, sta
 	if err != nil {
 		log.Fatalf("error writing metrics to file: %v", err)
 	}
+
+	var changedFacts []string
+	for key, configMetric := range configMetrics {
+		stateMetric, found := stateMetrics[key]
+		if found && !dtos.HasEqualFacts(stateMetric, configMetric, stateFactTemplates, factTemplates) {
+			changedFacts = append(changedFacts, configMetric.Metadata.Name)
+		}
+	}
+	h.markStaleMetricSources(stateRootLocation, changedFacts)
+}
+
+// markStaleMetricSources marks the metric sources of the metrics whose facts changed as stale,
+// the facts bound to the components are outdated until `component bind --refresh-facts` runs.
+func (h *ApplyHandler) markStaleMetricSources(stateRootLocation string, metricNames []string) {
+	if len(metricNames) == 0 {
+		return
+	}
+
+	components, errCState := yaml.Parse(yaml.GetStateInput(stateRootLocation), componentdtos.GetComponentUniqueKey)
+	if errCState != nil {
+		log.Fatalf("error: %v", errCState)
+	}
+
+	stale := 0
+	for _, component := range components {
+		for _, metricName := range metricNames {
+			if metricSource, exists := component.Spec.MetricSources[metricName]; exists {
+				metricSource.Status = componentdtos.StaleMetricSourceStatus
+				stale += 1
+			}
+		}
+	}
+	if stale == 0 {
+		return
+	}
+
+	state := make([]*componentdtos.ComponentDTO, 0, len(components))
+	for _, component := range components {
+		state = append(state, component)
+	}
+	err := yaml.WriteState(yaml.SortResults(state, componentdtos.GetComponentUniqueKey))
+	if err != nil {
+		log.Fatalf("error writing components to file: %v", err)
+	}
+
+	slices.Sort(metricNames)
+	fmt.Printf("Facts of metrics %s changed, %d metric sources are stale: run `component bind --refresh-facts` to bind them again\n", strings.Join(metricNames, ", "), stale)
+}
+
+// applyFactTemplates validates the fact templates of the config and stores them in the state,
+// where they are read when metrics are bound to components.
+func (h *ApplyHandler) applyFactTemplates(parseInput yaml.ParseInput) map[string]*dtos.FactTemplateDTO {
+	factTemplates, errConfig := yaml.Parse(parseInput, dtos.GetFactTemplateUniqueKey)
+	if errConfig != nil {
+		log.Fatalf("error: %v", errConfig)
+	}
+
+	result := make([]*dtos.FactTemplateDTO, 0, len(factTemplates))
+	for _, factTemplate := range factTemplates {
+		if validationErr := dtos.ValidateFactTemplate(factTemplate); validationErr != nil {
+			log.Fatalf("error: %v", validationErr)
+		}
+		result = append(result, factTemplate)
+	}
+
+	err := yaml.WriteState(yaml.SortResults(result, dtos.GetFactTemplateUniqueKey))
+	if err != nil {
+		log.Fatalf("error writing fact templates to file: %v", err)
+	}
+
+	return factTemplates
 }
 
 func (h *ApplyHandler) handleDeleted(ctx context.Context, metrics map[string]*dtos.MetricDTO) {
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"os"
+	"testing"
+
+	componentdtos "github.com/motain/of-catalog/internal/modules/component/dtos"
+	"github.com/motain/of-catalog/internal/utils/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestComponent(name string, metricSources ...string) *componentdtos.ComponentDTO {
+	component := &componentdtos.ComponentDTO{
+		Kind:     "Component",
+		Metadata: componentdtos.Metadata{Name: name},
+		Spec:     componentdtos.Spec{Name: name, MetricSources: map[string]*componentdtos.MetricSourceDTO{}},
+	}
+	for _, metricName := range metricSources {
+		component.Spec.MetricSources[metricName] = &componentdtos.MetricSourceDTO{Name: name + "-" + metricName}
+	}
+	return component
+}
+
+func TestApplyHandler_MarkStaleMetricSources(t *testing.T) {
+	tests := []struct {
+		name          string
+		metricNames   []string
+		expectedStale map[string][]string
+	}{
+		{
+			name:          "marks the metric sources of the changed metrics",
+			metricNames:   []string{"coverage"},
+			expectedStale: map[string][]string{"api": {"coverage"}, "worker": {"coverage"}},
+		},
+		{
+			name:          "leaves the metric sources of the other metrics",
+			metricNames:   []string{"owner"},
+			expectedStale: map[string][]string{"api": {"owner"}},
+		},
+		{
+			name:          "no changed metrics",
+			expectedStale: map[string][]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wd, err := os.Getwd()
+			require.NoError(t, err)
+			require.NoError(t, os.Chdir(t.TempDir()))
+			t.Cleanup(func() { os.Chdir(wd) })
+
+			state := []*componentdtos.ComponentDTO{
+				newTestComponent("api", "coverage", "owner"),
+				newTestComponent("worker", "coverage", "on-call"),
+			}
+			require.NoError(t, yaml.WriteState(state))
+
+			handler := NewApplyHandler(nil)
+			handler.markStaleMetricSources(yaml.StateLocation, tt.metricNames)
+
+			components, err := yaml.Parse(yaml.GetStateInput(yaml.StateLocation), componentdtos.GetComponentUniqueKey)
+			require.NoError(t, err)
+
+			stale := map[string][]string{}
+			for name, component := range components {
+				for metricName, metricSource := range component.Spec.MetricSources {
+					if metricSource.Status == componentdtos.StaleMetricSourceStatus {
+						stale[name] = append(stale[name], metricName)
+					}
+				}
+			}
+			assert.Equal(t, tt.expectedStale, stale)
+		})
+	}
+}
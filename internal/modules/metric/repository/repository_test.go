@@ -5,12 +5,12 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/golang/mock/gomock"
 	"github.com/motain/of-catalog/internal/modules/metric/repository"
 	"github.com/motain/of-catalog/internal/modules/metric/repository/dtos"
 	"github.com/motain/of-catalog/internal/modules/metric/resources"
 	compassservice "github.com/motain/of-catalog/internal/services/compassservice/mocks"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
 func TestRepository_Create(t *testing.T) {
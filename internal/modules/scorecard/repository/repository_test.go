@@ -5,8 +5,8 @@ import (
 	"errors"
 	"testing"
 
-	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 
 	"github.com/motain/of-catalog/internal/modules/scorecard/repository"
 	"github.com/motain/of-catalog/internal/modules/scorecard/repository/dtos"
@@ -0,0 +1,128 @@
+package awsservice
+
+//go:generate mockgen -destination=./mocks/mock_aws_client.go -package=awsservice github.com/motain/of-catalog/internal/services/awsservice AWSClientInterface
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/motain/of-catalog/internal/services/configservice"
+	"github.com/motain/of-catalog/internal/utils/awsutils"
+)
+
+// apiVersions lists the Query API version of the supported AWS services.
+var apiVersions = map[string]string{
+	"rds":         "2014-10-31",
+	"elasticache": "2015-02-02",
+}
+
+// AWSClientInterface defines the contract for calling AWS Query APIs (e.g. RDS, ElastiCache).
+type AWSClientInterface interface {
+	// Call invokes an action of an AWS service and returns the raw XML response
+	Call(service, action string, params url.Values) ([]byte, error)
+}
+
+// AWSClient implements AWSClientInterface by sending SigV4 signed requests to the AWS Query APIs.
+// The AWS configuration is loaded on first use using AWS_REGION and AWS_ROLE, endpoints can be
+// overridden with AWS_ENDPOINT_URL or AWS_ENDPOINT_URL_<SERVICE> (e.g. for local stand-ins).
+type AWSClient struct {
+	cfg configservice.ConfigServiceInterface
+
+	once    sync.Once
+	awsCfg  aws.Config
+	loadErr error
+}
+
+// NewAWSClient creates a new AWS client.
+// No credential lookup happens until the first call.
+//
+// Parameters:
+//   - cfg: Configuration service providing the AWS region, role and endpoints
+//
+// Returns:
+//   - AWSClientInterface: AWS client
+func NewAWSClient(cfg configservice.ConfigServiceInterface) AWSClientInterface {
+	return &AWSClient{cfg: cfg}
+}
+
+// Call invokes an action of an AWS Query API.
+// Returns the raw XML response, or an error carrying the AWS error code and message.
+func (c *AWSClient) Call(service, action string, params url.Values) ([]byte, error) {
+	version, supported := apiVersions[service]
+	if !supported {
+		return nil, fmt.Errorf("unsupported AWS service %s", service)
+	}
+
+	c.once.Do(func() {
+		c.awsCfg, c.loadErr = awsutils.LoadConfig(context.Background(), c.cfg.GetAWSRegion(), c.cfg.GetAWSRole())
+	})
+	if c.loadErr != nil {
+		return nil, c.loadErr
+	}
+
+	form := url.Values{}
+	for name, values := range params {
+		form[name] = values
+	}
+	form.Set("Action", action)
+	form.Set("Version", version)
+
+	req, reqErr := http.NewRequest(http.MethodPost, c.endpoint(service), strings.NewReader(form.Encode()))
+	if reqErr != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", action, reqErr)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	client := &http.Client{
+		Transport: &awsutils.SigV4RoundTripper{
+			Transport:   http.DefaultTransport,
+			Region:      c.awsCfg.Region,
+			Service:     service,
+			Credentials: c.awsCfg.Credentials,
+		},
+	}
+
+	resp, doErr := client.Do(req)
+	if doErr != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", action, doErr)
+	}
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", action, readErr)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var errorResponse struct {
+			Error struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			} `xml:"Error"`
+		}
+		if xml.Unmarshal(body, &errorResponse) == nil && errorResponse.Error.Code != "" {
+			return nil, fmt.Errorf("%s failed: %s: %s", action, errorResponse.Error.Code, errorResponse.Error.Message)
+		}
+		return nil, fmt.Errorf("%s failed with status %d", action, resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+// endpoint resolves the URL of an AWS service, honoring the standard endpoint overrides.
+func (c *AWSClient) endpoint(service string) string {
+	if endpoint := c.cfg.Get("AWS_ENDPOINT_URL_" + strings.ToUpper(service)); endpoint != "" {
+		return endpoint
+	}
+	if c.awsCfg.BaseEndpoint != nil && *c.awsCfg.BaseEndpoint != "" {
+		return *c.awsCfg.BaseEndpoint
+	}
+	return fmt.Sprintf("https://%s.%s.amazonaws.com/", service, c.awsCfg.Region)
+}
@@ -0,0 +1,179 @@
+package awsservice
+
+//go:generate mockgen -destination=./mocks/mock_aws_service.go -package=awsservice github.com/motain/of-catalog/internal/services/awsservice AWSServiceInterface
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+)
+
+// ResourceType identifies the kind of AWS resource to describe.
+type ResourceType string
+
+const (
+	RDSClusterResource                  ResourceType = "rds-cluster"
+	RDSInstanceResource                 ResourceType = "rds-instance"
+	ElastiCacheReplicationGroupResource ResourceType = "elasticache-replication-group"
+	ElastiCacheClusterResource          ResourceType = "elasticache-cluster"
+)
+
+// AWSServiceInterface defines the contract for inspecting AWS resources.
+type AWSServiceInterface interface {
+	// DescribeResource returns the normalized description of an AWS resource
+	DescribeResource(resourceType, identifier string) (*Resource, error)
+}
+
+// AWSService implements AWSServiceInterface on top of the AWS Query APIs.
+type AWSService struct {
+	client AWSClientInterface
+}
+
+// NewAWSService creates a new AWSService instance with the provided client.
+//
+// Parameters:
+//   - client: The AWSClientInterface implementation to use for API communication
+//
+// Returns:
+//   - *AWSService: A new service instance
+func NewAWSService(client AWSClientInterface) *AWSService {
+	return &AWSService{client: client}
+}
+
+// DescribeResource describes an RDS cluster or instance, or an ElastiCache replication group or cluster.
+//
+// Parameters:
+//   - resourceType: One of rds-cluster, rds-instance, elasticache-replication-group, elasticache-cluster
+//   - identifier: The identifier of the resource (e.g. the DB cluster identifier)
+//
+// Returns:
+//   - *Resource: The normalized resource, including its tags
+//   - error: Returned when the resource type is unknown, the resource does not exist or the call fails
+func (s *AWSService) DescribeResource(resourceType, identifier string) (*Resource, error) {
+	if identifier == "" {
+		return nil, fmt.Errorf("missing identifier for %s", resourceType)
+	}
+
+	switch ResourceType(resourceType) {
+	case RDSClusterResource:
+		return s.describeDBCluster(identifier)
+	case RDSInstanceResource:
+		return s.describeDBInstance(identifier)
+	case ElastiCacheReplicationGroupResource:
+		return s.describeReplicationGroup(identifier)
+	case ElastiCacheClusterResource:
+		return s.describeCacheCluster(identifier)
+	default:
+		return nil, fmt.Errorf("unknown AWS resource type %s", resourceType)
+	}
+}
+
+func (s *AWSService) describeDBCluster(identifier string) (*Resource, error) {
+	var response describeDBClustersResponse
+	if err := s.call("rds", "DescribeDBClusters", url.Values{"DBClusterIdentifier": {identifier}}, &response); err != nil {
+		return nil, err
+	}
+	if len(response.DBClusters) == 0 {
+		return nil, fmt.Errorf("rds cluster %s not found", identifier)
+	}
+
+	resource := response.DBClusters[0].toResource()
+	return &resource, nil
+}
+
+func (s *AWSService) describeDBInstance(identifier string) (*Resource, error) {
+	var response describeDBInstancesResponse
+	if err := s.call("rds", "DescribeDBInstances", url.Values{"DBInstanceIdentifier": {identifier}}, &response); err != nil {
+		return nil, err
+	}
+	if len(response.DBInstances) == 0 {
+		return nil, fmt.Errorf("rds instance %s not found", identifier)
+	}
+
+	resource := response.DBInstances[0].toResource()
+	return &resource, nil
+}
+
+// describeReplicationGroup describes the replication group, reading the engine version from its first
+// member cluster since replication groups do not report it.
+func (s *AWSService) describeReplicationGroup(identifier string) (*Resource, error) {
+	var response describeReplicationGroupsResponse
+	if err := s.call("elasticache", "DescribeReplicationGroups", url.Values{"ReplicationGroupId": {identifier}}, &response); err != nil {
+		return nil, err
+	}
+	if len(response.ReplicationGroups) == 0 {
+		return nil, fmt.Errorf("elasticache replication group %s not found", identifier)
+	}
+
+	group := response.ReplicationGroups[0]
+	resource := group.toResource()
+
+	if len(group.MemberClusters) > 0 {
+		member, memberErr := s.cacheCluster(group.MemberClusters[0])
+		if memberErr != nil {
+			return nil, memberErr
+		}
+		resource.EngineVersion = member.EngineVersion
+		if resource.Engine == "" {
+			resource.Engine = member.Engine
+		}
+	}
+
+	tags, tagsErr := s.listTags(resource.ARN)
+	if tagsErr != nil {
+		return nil, tagsErr
+	}
+	resource.Tags = tags
+
+	return &resource, nil
+}
+
+func (s *AWSService) describeCacheCluster(identifier string) (*Resource, error) {
+	cluster, clusterErr := s.cacheCluster(identifier)
+	if clusterErr != nil {
+		return nil, clusterErr
+	}
+
+	resource := cluster.toResource()
+
+	tags, tagsErr := s.listTags(resource.ARN)
+	if tagsErr != nil {
+		return nil, tagsErr
+	}
+	resource.Tags = tags
+
+	return &resource, nil
+}
+
+func (s *AWSService) cacheCluster(identifier string) (cacheCluster, error) {
+	var response describeCacheClustersResponse
+	if err := s.call("elasticache", "DescribeCacheClusters", url.Values{"CacheClusterId": {identifier}}, &response); err != nil {
+		return cacheCluster{}, err
+	}
+	if len(response.CacheClusters) == 0 {
+		return cacheCluster{}, fmt.Errorf("elasticache cluster %s not found", identifier)
+	}
+
+	return response.CacheClusters[0], nil
+}
+
+// listTags returns the tags of an ElastiCache resource, RDS returns them with the description.
+func (s *AWSService) listTags(arn string) (map[string]string, error) {
+	var response listTagsForResourceResponse
+	if err := s.call("elasticache", "ListTagsForResource", url.Values{"ResourceName": {arn}}, &response); err != nil {
+		return nil, err
+	}
+	return tagsToMap(response.TagList), nil
+}
+
+func (s *AWSService) call(service, action string, params url.Values, response interface{}) error {
+	body, callErr := s.client.Call(service, action, params)
+	if callErr != nil {
+		return callErr
+	}
+
+	if err := xml.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", action, err)
+	}
+	return nil
+}
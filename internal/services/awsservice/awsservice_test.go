@@ -0,0 +1,205 @@
+package awsservice_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/awsservice"
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// responses of the local AWS API stand-in, by action
+var responses = map[string]string{
+	"GetCallerIdentity": `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::123456789012:user/test</Arn>
+    <UserId>AIDTEST</UserId>
+    <Account>123456789012</Account>
+  </GetCallerIdentityResult>
+  <ResponseMetadata><RequestId>1</RequestId></ResponseMetadata>
+</GetCallerIdentityResponse>`,
+	"DescribeDBClusters": `<DescribeDBClustersResponse xmlns="http://rds.amazonaws.com/doc/2014-10-31/">
+  <DescribeDBClustersResult>
+    <DBClusters>
+      <DBCluster>
+        <DBClusterIdentifier>orders-db</DBClusterIdentifier>
+        <DBClusterArn>arn:aws:rds:eu-west-1:123456789012:cluster:orders-db</DBClusterArn>
+        <Status>available</Status>
+        <Engine>aurora-postgresql</Engine>
+        <EngineVersion>15.4</EngineVersion>
+        <BackupRetentionPeriod>7</BackupRetentionPeriod>
+        <MultiAZ>true</MultiAZ>
+        <StorageEncrypted>true</StorageEncrypted>
+        <DeletionProtection>false</DeletionProtection>
+        <TagList>
+          <Tag><Key>team</Key><Value>checkout</Value></Tag>
+        </TagList>
+      </DBCluster>
+    </DBClusters>
+  </DescribeDBClustersResult>
+</DescribeDBClustersResponse>`,
+	"DescribeReplicationGroups": `<DescribeReplicationGroupsResponse xmlns="http://elasticache.amazonaws.com/doc/2015-02-02/">
+  <DescribeReplicationGroupsResult>
+    <ReplicationGroups>
+      <ReplicationGroup>
+        <ReplicationGroupId>sessions</ReplicationGroupId>
+        <ARN>arn:aws:elasticache:eu-west-1:123456789012:replicationgroup:sessions</ARN>
+        <Status>available</Status>
+        <MultiAZ>enabled</MultiAZ>
+        <SnapshotRetentionLimit>1</SnapshotRetentionLimit>
+        <AtRestEncryptionEnabled>true</AtRestEncryptionEnabled>
+        <TransitEncryptionEnabled>false</TransitEncryptionEnabled>
+        <MemberClusters>
+          <ClusterId>sessions-001</ClusterId>
+          <ClusterId>sessions-002</ClusterId>
+        </MemberClusters>
+      </ReplicationGroup>
+    </ReplicationGroups>
+  </DescribeReplicationGroupsResult>
+</DescribeReplicationGroupsResponse>`,
+	"DescribeCacheClusters": `<DescribeCacheClustersResponse xmlns="http://elasticache.amazonaws.com/doc/2015-02-02/">
+  <DescribeCacheClustersResult>
+    <CacheClusters>
+      <CacheCluster>
+        <CacheClusterId>sessions-001</CacheClusterId>
+        <Engine>redis</Engine>
+        <EngineVersion>7.1.0</EngineVersion>
+      </CacheCluster>
+    </CacheClusters>
+  </DescribeCacheClustersResult>
+</DescribeCacheClustersResponse>`,
+	"ListTagsForResource": `<ListTagsForResourceResponse xmlns="http://elasticache.amazonaws.com/doc/2015-02-02/">
+  <ListTagsForResourceResult>
+    <TagList>
+      <Tag><Key>team</Key><Value>identity</Value></Tag>
+    </TagList>
+  </ListTagsForResourceResult>
+</ListTagsForResourceResponse>`,
+	"DescribeDBInstances": `<ErrorResponse xmlns="http://rds.amazonaws.com/doc/2014-10-31/">
+  <Error><Type>Sender</Type><Code>DBInstanceNotFound</Code><Message>DBInstance missing-db not found.</Message></Error>
+</ErrorResponse>`,
+}
+
+func newAWSStandIn(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIATEST/"))
+
+		action := r.Form.Get("Action")
+		response, exists := responses[action]
+		if !exists {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(response, "<ErrorResponse") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = fmt.Fprint(w, response)
+	}))
+
+	// The AWS SDK reads the credentials and the endpoint of the stand-in from the environment
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIATEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_ENDPOINT_URL", server.URL)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	return server
+}
+
+func TestAWSService_DescribeResource(t *testing.T) {
+	server := newAWSStandIn(t)
+	defer server.Close()
+
+	deletionProtection := false
+	transitEncrypted := false
+
+	tests := []struct {
+		name         string
+		resourceType string
+		identifier   string
+		expected     *awsservice.Resource
+		expectedErr  string
+	}{
+		{
+			name:         "rds cluster",
+			resourceType: "rds-cluster",
+			identifier:   "orders-db",
+			expected: &awsservice.Resource{
+				Type:                  "rds-cluster",
+				ID:                    "orders-db",
+				ARN:                   "arn:aws:rds:eu-west-1:123456789012:cluster:orders-db",
+				Status:                "available",
+				Engine:                "aurora-postgresql",
+				EngineVersion:         "15.4",
+				BackupRetentionPeriod: 7,
+				MultiAZ:               true,
+				StorageEncrypted:      true,
+				DeletionProtection:    &deletionProtection,
+				Tags:                  map[string]string{"team": "checkout"},
+			},
+		},
+		{
+			name:         "elasticache replication group",
+			resourceType: "elasticache-replication-group",
+			identifier:   "sessions",
+			expected: &awsservice.Resource{
+				Type:                  "elasticache-replication-group",
+				ID:                    "sessions",
+				ARN:                   "arn:aws:elasticache:eu-west-1:123456789012:replicationgroup:sessions",
+				Status:                "available",
+				Engine:                "redis",
+				EngineVersion:         "7.1.0",
+				BackupRetentionPeriod: 1,
+				MultiAZ:               true,
+				StorageEncrypted:      true,
+				TransitEncrypted:      &transitEncrypted,
+				Tags:                  map[string]string{"team": "identity"},
+			},
+		},
+		{
+			name:         "aws error",
+			resourceType: "rds-instance",
+			identifier:   "missing-db",
+			expectedErr:  "DescribeDBInstances failed: DBInstanceNotFound: DBInstance missing-db not found.",
+		},
+		{
+			name:         "unknown resource type",
+			resourceType: "s3-bucket",
+			identifier:   "assets",
+			expectedErr:  "unknown AWS resource type s3-bucket",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+			mockConfig.EXPECT().GetAWSRegion().Return("eu-west-1").AnyTimes()
+			mockConfig.EXPECT().GetAWSRole().Return("").AnyTimes()
+			mockConfig.EXPECT().Get(gomock.Any()).Return("").AnyTimes()
+
+			service := awsservice.NewAWSService(awsservice.NewAWSClient(mockConfig))
+
+			resource, err := service.DescribeResource(tt.resourceType, tt.identifier)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, resource)
+		})
+	}
+}
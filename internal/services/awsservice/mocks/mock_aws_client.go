@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/motain/of-catalog/internal/services/awsservice (interfaces: AWSClientInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_aws_client.go -package=awsservice github.com/motain/of-catalog/internal/services/awsservice AWSClientInterface
+//
+
+// Package awsservice is a generated GoMock package.
+package awsservice
+
+import (
+	url "net/url"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAWSClientInterface is a mock of AWSClientInterface interface.
+type MockAWSClientInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAWSClientInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAWSClientInterfaceMockRecorder is the mock recorder for MockAWSClientInterface.
+type MockAWSClientInterfaceMockRecorder struct {
+	mock *MockAWSClientInterface
+}
+
+// NewMockAWSClientInterface creates a new mock instance.
+func NewMockAWSClientInterface(ctrl *gomock.Controller) *MockAWSClientInterface {
+	mock := &MockAWSClientInterface{ctrl: ctrl}
+	mock.recorder = &MockAWSClientInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAWSClientInterface) EXPECT() *MockAWSClientInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Call mocks base method.
+func (m *MockAWSClientInterface) Call(service, action string, params url.Values) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Call", service, action, params)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Call indicates an expected call of Call.
+func (mr *MockAWSClientInterfaceMockRecorder) Call(service, action, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Call", reflect.TypeOf((*MockAWSClientInterface)(nil).Call), service, action, params)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/motain/of-catalog/internal/services/awsservice (interfaces: AWSServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_aws_service.go -package=awsservice github.com/motain/of-catalog/internal/services/awsservice AWSServiceInterface
+//
+
+// Package awsservice is a generated GoMock package.
+package awsservice
+
+import (
+	reflect "reflect"
+
+	awsservice "github.com/motain/of-catalog/internal/services/awsservice"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAWSServiceInterface is a mock of AWSServiceInterface interface.
+type MockAWSServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAWSServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAWSServiceInterfaceMockRecorder is the mock recorder for MockAWSServiceInterface.
+type MockAWSServiceInterfaceMockRecorder struct {
+	mock *MockAWSServiceInterface
+}
+
+// NewMockAWSServiceInterface creates a new mock instance.
+func NewMockAWSServiceInterface(ctrl *gomock.Controller) *MockAWSServiceInterface {
+	mock := &MockAWSServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAWSServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAWSServiceInterface) EXPECT() *MockAWSServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DescribeResource mocks base method.
+func (m *MockAWSServiceInterface) DescribeResource(resourceType, identifier string) (*awsservice.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeResource", resourceType, identifier)
+	ret0, _ := ret[0].(*awsservice.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeResource indicates an expected call of DescribeResource.
+func (mr *MockAWSServiceInterfaceMockRecorder) DescribeResource(resourceType, identifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeResource", reflect.TypeOf((*MockAWSServiceInterface)(nil).DescribeResource), resourceType, identifier)
+}
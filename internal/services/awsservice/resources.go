@@ -0,0 +1,164 @@
+package awsservice
+
+import "strings"
+
+// Resource is the normalized description of an AWS resource, returned as JSON to the fact system.
+// Fields not supported by a resource type are omitted (e.g. deletionProtection for ElastiCache).
+type Resource struct {
+	Type                  string            `json:"type"`
+	ID                    string            `json:"id"`
+	ARN                   string            `json:"arn"`
+	Status                string            `json:"status"`
+	Engine                string            `json:"engine"`
+	EngineVersion         string            `json:"engineVersion"`
+	BackupRetentionPeriod int               `json:"backupRetentionPeriod"`
+	MultiAZ               bool              `json:"multiAZ"`
+	StorageEncrypted      bool              `json:"storageEncrypted"`
+	TransitEncrypted      *bool             `json:"transitEncrypted,omitempty"`
+	DeletionProtection    *bool             `json:"deletionProtection,omitempty"`
+	Tags                  map[string]string `json:"tags"`
+}
+
+type tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+func tagsToMap(tags []tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, t := range tags {
+		result[t.Key] = t.Value
+	}
+	return result
+}
+
+type dbCluster struct {
+	DBClusterIdentifier   string `xml:"DBClusterIdentifier"`
+	DBClusterArn          string `xml:"DBClusterArn"`
+	Status                string `xml:"Status"`
+	Engine                string `xml:"Engine"`
+	EngineVersion         string `xml:"EngineVersion"`
+	BackupRetentionPeriod int    `xml:"BackupRetentionPeriod"`
+	MultiAZ               bool   `xml:"MultiAZ"`
+	StorageEncrypted      bool   `xml:"StorageEncrypted"`
+	DeletionProtection    bool   `xml:"DeletionProtection"`
+	TagList               []tag  `xml:"TagList>Tag"`
+}
+
+type describeDBClustersResponse struct {
+	DBClusters []dbCluster `xml:"DescribeDBClustersResult>DBClusters>DBCluster"`
+}
+
+func (c dbCluster) toResource() Resource {
+	return Resource{
+		Type:                  string(RDSClusterResource),
+		ID:                    c.DBClusterIdentifier,
+		ARN:                   c.DBClusterArn,
+		Status:                c.Status,
+		Engine:                c.Engine,
+		EngineVersion:         c.EngineVersion,
+		BackupRetentionPeriod: c.BackupRetentionPeriod,
+		MultiAZ:               c.MultiAZ,
+		StorageEncrypted:      c.StorageEncrypted,
+		DeletionProtection:    &c.DeletionProtection,
+		Tags:                  tagsToMap(c.TagList),
+	}
+}
+
+type dbInstance struct {
+	DBInstanceIdentifier  string `xml:"DBInstanceIdentifier"`
+	DBInstanceArn         string `xml:"DBInstanceArn"`
+	DBInstanceStatus      string `xml:"DBInstanceStatus"`
+	Engine                string `xml:"Engine"`
+	EngineVersion         string `xml:"EngineVersion"`
+	BackupRetentionPeriod int    `xml:"BackupRetentionPeriod"`
+	MultiAZ               bool   `xml:"MultiAZ"`
+	StorageEncrypted      bool   `xml:"StorageEncrypted"`
+	DeletionProtection    bool   `xml:"DeletionProtection"`
+	TagList               []tag  `xml:"TagList>Tag"`
+}
+
+type describeDBInstancesResponse struct {
+	DBInstances []dbInstance `xml:"DescribeDBInstancesResult>DBInstances>DBInstance"`
+}
+
+func (i dbInstance) toResource() Resource {
+	return Resource{
+		Type:                  string(RDSInstanceResource),
+		ID:                    i.DBInstanceIdentifier,
+		ARN:                   i.DBInstanceArn,
+		Status:                i.DBInstanceStatus,
+		Engine:                i.Engine,
+		EngineVersion:         i.EngineVersion,
+		BackupRetentionPeriod: i.BackupRetentionPeriod,
+		MultiAZ:               i.MultiAZ,
+		StorageEncrypted:      i.StorageEncrypted,
+		DeletionProtection:    &i.DeletionProtection,
+		Tags:                  tagsToMap(i.TagList),
+	}
+}
+
+type replicationGroup struct {
+	ReplicationGroupID       string   `xml:"ReplicationGroupId"`
+	ARN                      string   `xml:"ARN"`
+	Status                   string   `xml:"Status"`
+	Engine                   string   `xml:"Engine"`
+	MultiAZ                  string   `xml:"MultiAZ"`
+	SnapshotRetentionLimit   int      `xml:"SnapshotRetentionLimit"`
+	AtRestEncryptionEnabled  bool     `xml:"AtRestEncryptionEnabled"`
+	TransitEncryptionEnabled bool     `xml:"TransitEncryptionEnabled"`
+	MemberClusters           []string `xml:"MemberClusters>ClusterId"`
+}
+
+type describeReplicationGroupsResponse struct {
+	ReplicationGroups []replicationGroup `xml:"DescribeReplicationGroupsResult>ReplicationGroups>ReplicationGroup"`
+}
+
+func (g replicationGroup) toResource() Resource {
+	return Resource{
+		Type:                  string(ElastiCacheReplicationGroupResource),
+		ID:                    g.ReplicationGroupID,
+		ARN:                   g.ARN,
+		Status:                g.Status,
+		Engine:                g.Engine,
+		BackupRetentionPeriod: g.SnapshotRetentionLimit,
+		MultiAZ:               strings.EqualFold(g.MultiAZ, "enabled"),
+		StorageEncrypted:      g.AtRestEncryptionEnabled,
+		TransitEncrypted:      &g.TransitEncryptionEnabled,
+	}
+}
+
+type cacheCluster struct {
+	CacheClusterID           string `xml:"CacheClusterId"`
+	ARN                      string `xml:"ARN"`
+	CacheClusterStatus       string `xml:"CacheClusterStatus"`
+	Engine                   string `xml:"Engine"`
+	EngineVersion            string `xml:"EngineVersion"`
+	SnapshotRetentionLimit   int    `xml:"SnapshotRetentionLimit"`
+	AtRestEncryptionEnabled  bool   `xml:"AtRestEncryptionEnabled"`
+	TransitEncryptionEnabled bool   `xml:"TransitEncryptionEnabled"`
+	AZMode                   string `xml:"AZMode"`
+}
+
+type describeCacheClustersResponse struct {
+	CacheClusters []cacheCluster `xml:"DescribeCacheClustersResult>CacheClusters>CacheCluster"`
+}
+
+func (c cacheCluster) toResource() Resource {
+	return Resource{
+		Type:                  string(ElastiCacheClusterResource),
+		ID:                    c.CacheClusterID,
+		ARN:                   c.ARN,
+		Status:                c.CacheClusterStatus,
+		Engine:                c.Engine,
+		EngineVersion:         c.EngineVersion,
+		BackupRetentionPeriod: c.SnapshotRetentionLimit,
+		MultiAZ:               c.AZMode == "cross-az",
+		StorageEncrypted:      c.AtRestEncryptionEnabled,
+		TransitEncrypted:      &c.TransitEncryptionEnabled,
+	}
+}
+
+type listTagsForResourceResponse struct {
+	TagList []tag `xml:"ListTagsForResourceResult>TagList>Tag"`
+}
@@ -0,0 +1,78 @@
+package codesearchservice
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extractTarball writes the regular files of a GitHub tarball into dir.
+// GitHub wraps the tree in a single "<owner>-<repo>-<sha>" directory which is stripped.
+func extractTarball(r io.Reader, dir string) error {
+	gz, gzErr := gzip.NewReader(r)
+	if gzErr != nil {
+		return gzErr
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, nextErr := tr.Next()
+		if nextErr == io.EOF {
+			return nil
+		}
+		if nextErr != nil {
+			return nextErr
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target, pathErr := targetPath(dir, header.Name)
+		if pathErr != nil {
+			return pathErr
+		}
+		if target == "" {
+			continue
+		}
+
+		if writeErr := writeFile(target, tr); writeErr != nil {
+			return writeErr
+		}
+	}
+}
+
+// targetPath maps an archive entry to its location below dir, rejecting entries escaping it.
+func targetPath(dir, name string) (string, error) {
+	_, relative, found := strings.Cut(filepath.ToSlash(name), "/")
+	if !found || relative == "" {
+		return "", nil
+	}
+
+	target := filepath.Join(dir, filepath.FromSlash(relative))
+	if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid archive entry %s", name)
+	}
+
+	return target, nil
+}
+
+func writeFile(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, createErr := os.Create(target)
+	if createErr != nil {
+		return createErr
+	}
+	defer file.Close()
+
+	_, copyErr := io.Copy(file, r)
+	return copyErr
+}
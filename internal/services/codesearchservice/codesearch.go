@@ -0,0 +1,103 @@
+package codesearchservice
+
+//go:generate mockgen -destination=./mocks/mock_codesearch_service.go -package=codesearchservice github.com/motain/of-catalog/internal/services/codesearchservice CodeSearchServiceInterface
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/motain/of-catalog/internal/services/configservice"
+	"github.com/motain/of-catalog/internal/services/githubservice"
+)
+
+// CodeSearchServiceInterface searches the content of a repository from a local copy of its tree.
+type CodeSearchServiceInterface interface {
+	// Search runs query against the repository tree at ref (default branch when empty).
+	// When isRegex is true the query is interpreted as a regular expression, otherwise as a literal string.
+	Search(repo, ref, query string, isRegex bool) ([]Match, error)
+}
+
+// CodeSearchService downloads each repository at most once per run into a local cache
+// and scans the extracted tree, so that multiple facts on the same repository share one download.
+type CodeSearchService struct {
+	github    githubservice.GitHubServiceInterface
+	cacheRoot string
+
+	mu        sync.Mutex
+	checkouts map[string]*checkout
+}
+
+type checkout struct {
+	once sync.Once
+	dir  string
+	err  error
+}
+
+func NewCodeSearchService(
+	config configservice.ConfigServiceInterface,
+	github githubservice.GitHubServiceInterface,
+) *CodeSearchService {
+	return &CodeSearchService{
+		github:    github,
+		cacheRoot: config.GetRepoCacheDir(),
+		checkouts: make(map[string]*checkout),
+	}
+}
+
+func (cs *CodeSearchService) Search(repo, ref, query string, isRegex bool) ([]Match, error) {
+	dir, checkoutErr := cs.checkout(repo, ref)
+	if checkoutErr != nil {
+		return nil, checkoutErr
+	}
+
+	return ScanDir(dir, query, isRegex)
+}
+
+// checkout returns the local directory holding the tree of repo at ref, downloading it on first use.
+func (cs *CodeSearchService) checkout(repo, ref string) (string, error) {
+	key := cacheKey(repo, ref)
+
+	cs.mu.Lock()
+	co, exists := cs.checkouts[key]
+	if !exists {
+		co = &checkout{}
+		cs.checkouts[key] = co
+	}
+	cs.mu.Unlock()
+
+	co.once.Do(func() {
+		co.dir, co.err = cs.download(repo, ref, filepath.Join(cs.cacheRoot, key))
+	})
+
+	return co.dir, co.err
+}
+
+func (cs *CodeSearchService) download(repo, ref, dir string) (string, error) {
+	tarball, downloadErr := cs.github.DownloadTarball(repo, ref)
+	if downloadErr != nil {
+		return "", fmt.Errorf("failed to download %s: %w", repo, downloadErr)
+	}
+	defer tarball.Close()
+
+	// Whatever is left from a previous run is stale, each run starts from a fresh tree
+	if removeErr := os.RemoveAll(dir); removeErr != nil {
+		return "", fmt.Errorf("failed to clean cache directory %s: %w", dir, removeErr)
+	}
+
+	if extractErr := extractTarball(tarball, dir); extractErr != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", repo, extractErr)
+	}
+
+	return dir, nil
+}
+
+func cacheKey(repo, ref string) string {
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(fmt.Sprintf("%s@%s", repo, ref))
+}
@@ -0,0 +1,106 @@
+package codesearchservice_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/codesearchservice"
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	githubservice "github.com/motain/of-catalog/internal/services/githubservice/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func buildTarball(t *testing.T, files map[string]string) io.ReadCloser {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	gz := gzip.NewWriter(&buffer)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		assert.NoError(t, tw.WriteHeader(header))
+		_, writeErr := tw.Write([]byte(content))
+		assert.NoError(t, writeErr)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+
+	return io.NopCloser(&buffer)
+}
+
+func TestCodeSearchService_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cacheDir := t.TempDir()
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	mockConfig.EXPECT().GetRepoCacheDir().Return(cacheDir)
+	mockGitHub := githubservice.NewMockGitHubServiceInterface(ctrl)
+
+	// The tarball must be downloaded only once for multiple searches on the same ref
+	mockGitHub.EXPECT().
+		DownloadTarball("my-repo", "main").
+		Return(buildTarball(t, map[string]string{
+			"motain-my-repo-abc123/.github/workflows/deploy.yaml": "steps:\n  - uses: motain/onefootball-actions/paas-deploy@master\n",
+			"motain-my-repo-abc123/README.md":                     "# my-repo\n",
+		}), nil).
+		Times(1)
+
+	cs := codesearchservice.NewCodeSearchService(mockConfig, mockGitHub)
+
+	matches, err := cs.Search("my-repo", "main", "paas-deploy@master", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []codesearchservice.Match{
+		{File: ".github/workflows/deploy.yaml", Line: 2, Text: "- uses: motain/onefootball-actions/paas-deploy@master"},
+	}, matches)
+
+	matches, err = cs.Search("my-repo", "main", `^#\s+my-`, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []codesearchservice.Match{{File: "README.md", Line: 1, Text: "# my-repo"}}, matches)
+
+	_, statErr := os.Stat(filepath.Join(cacheDir, "my-repo@main", "README.md"))
+	assert.NoError(t, statErr)
+}
+
+func TestCodeSearchService_SearchDownloadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	mockConfig.EXPECT().GetRepoCacheDir().Return(t.TempDir())
+	mockGitHub := githubservice.NewMockGitHubServiceInterface(ctrl)
+	mockGitHub.EXPECT().DownloadTarball("my-repo", "").Return(nil, errors.New("404 Not Found")).Times(1)
+
+	cs := codesearchservice.NewCodeSearchService(mockConfig, mockGitHub)
+
+	_, err := cs.Search("my-repo", "", "foo", false)
+	assert.EqualError(t, err, "failed to download my-repo: 404 Not Found")
+
+	// Failures are cached as well, the download is not retried within the same run
+	_, err = cs.Search("my-repo", "", "bar", false)
+	assert.Error(t, err)
+}
+
+func TestCodeSearchService_SearchRejectsPathTraversal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	mockConfig.EXPECT().GetRepoCacheDir().Return(t.TempDir())
+	mockGitHub := githubservice.NewMockGitHubServiceInterface(ctrl)
+	mockGitHub.EXPECT().
+		DownloadTarball("my-repo", "").
+		Return(buildTarball(t, map[string]string{"motain-my-repo-abc123/../../evil": "boom"}), nil)
+
+	cs := codesearchservice.NewCodeSearchService(mockConfig, mockGitHub)
+
+	_, err := cs.Search("my-repo", "", "boom", false)
+	assert.ErrorContains(t, err, "invalid archive entry")
+}
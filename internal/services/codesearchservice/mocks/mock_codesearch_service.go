@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/motain/of-catalog/internal/services/codesearchservice (interfaces: CodeSearchServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=./mocks/mock_codesearch_service.go -package=codesearchservice github.com/motain/of-catalog/internal/services/codesearchservice CodeSearchServiceInterface
+//
+
+// Package codesearchservice is a generated GoMock package.
+package codesearchservice
+
+import (
+	reflect "reflect"
+
+	codesearchservice "github.com/motain/of-catalog/internal/services/codesearchservice"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCodeSearchServiceInterface is a mock of CodeSearchServiceInterface interface.
+type MockCodeSearchServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodeSearchServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCodeSearchServiceInterfaceMockRecorder is the mock recorder for MockCodeSearchServiceInterface.
+type MockCodeSearchServiceInterfaceMockRecorder struct {
+	mock *MockCodeSearchServiceInterface
+}
+
+// NewMockCodeSearchServiceInterface creates a new mock instance.
+func NewMockCodeSearchServiceInterface(ctrl *gomock.Controller) *MockCodeSearchServiceInterface {
+	mock := &MockCodeSearchServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCodeSearchServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodeSearchServiceInterface) EXPECT() *MockCodeSearchServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockCodeSearchServiceInterface) Search(repo, ref, query string, isRegex bool) ([]codesearchservice.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", repo, ref, query, isRegex)
+	ret0, _ := ret[0].([]codesearchservice.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockCodeSearchServiceInterfaceMockRecorder) Search(repo, ref, query, isRegex any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockCodeSearchServiceInterface)(nil).Search), repo, ref, query, isRegex)
+}
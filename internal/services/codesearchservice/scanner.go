@@ -0,0 +1,119 @@
+package codesearchservice
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Files bigger than this are assumed to be generated or binary assets and are not scanned
+	maxScannedFileSize = 1 << 20
+	// Number of leading bytes inspected to detect binary files
+	binarySniffLength = 8000
+)
+
+// Match is a single line of a file matching a search query.
+type Match struct {
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
+// ScanDir walks root and returns every line matching query.
+// File paths in the result are relative to root and use forward slashes.
+func ScanDir(root, query string, isRegex bool) ([]Match, error) {
+	match, matcherErr := newMatcher(query, isRegex)
+	if matcherErr != nil {
+		return nil, matcherErr
+	}
+
+	matches := make([]Match, 0)
+	walkErr := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		fileMatches, scanErr := scanFile(path, match)
+		if scanErr != nil {
+			return scanErr
+		}
+
+		relative, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return relErr
+		}
+
+		for _, m := range fileMatches {
+			m.File = filepath.ToSlash(relative)
+			matches = append(matches, m)
+		}
+
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	return matches, nil
+}
+
+func newMatcher(query string, isRegex bool) (func(line string) bool, error) {
+	if !isRegex {
+		return func(line string) bool { return strings.Contains(line, query) }, nil
+	}
+
+	re, compileErr := regexp.Compile(query)
+	if compileErr != nil {
+		return nil, compileErr
+	}
+
+	return re.MatchString, nil
+}
+
+func scanFile(path string, match func(line string) bool) ([]Match, error) {
+	info, statErr := os.Stat(path)
+	if statErr != nil {
+		return nil, statErr
+	}
+
+	if info.Size() > maxScannedFileSize {
+		return nil, nil
+	}
+
+	content, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	if bytes.IndexByte(content[:min(len(content), binarySniffLength)], 0) != -1 {
+		return nil, nil
+	}
+
+	var matches []Match
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScannedFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if match(text) {
+			matches = append(matches, Match{Line: line, Text: strings.TrimSpace(text)})
+		}
+	}
+
+	return matches, scanner.Err()
+}
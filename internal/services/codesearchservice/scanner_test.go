@@ -0,0 +1,76 @@
+package codesearchservice_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/codesearchservice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanDir(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app.toml":            "[service]\nreplicas_min = 3\nreplicas_max = 6\n",
+		"src/main.go":         "package main\n\nfunc main() {\n\t// replicas are set in app.toml\n}\n",
+		".git/config":         "replicas = 1\n",
+		"assets/logo.png":     "replicas\x00\x01\x02",
+		"docs/empty/.gitkeep": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		isRegex   bool
+		expected  []codesearchservice.Match
+		expectErr bool
+	}{
+		{
+			name:  "literal search skips .git and binary files",
+			query: "replicas",
+			expected: []codesearchservice.Match{
+				{File: "app.toml", Line: 2, Text: "replicas_min = 3"},
+				{File: "app.toml", Line: 3, Text: "replicas_max = 6"},
+				{File: "src/main.go", Line: 4, Text: "// replicas are set in app.toml"},
+			},
+		},
+		{
+			name:    "regex search",
+			query:   `^replicas_m(in|ax) = [3-5]$`,
+			isRegex: true,
+			expected: []codesearchservice.Match{
+				{File: "app.toml", Line: 2, Text: "replicas_min = 3"},
+			},
+		},
+		{
+			name:     "literal search does not interpret regex syntax",
+			query:    "replicas_m(in|ax)",
+			expected: []codesearchservice.Match{},
+		},
+		{
+			name:      "invalid regex",
+			query:     "replicas_(",
+			isRegex:   true,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := codesearchservice.ScanDir(root, tt.query, tt.isRegex)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, matches)
+		})
+	}
+}
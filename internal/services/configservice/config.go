@@ -5,6 +5,7 @@ package configservice
 import (
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +21,8 @@ type ConfigServiceInterface interface {
 	GetPrometheusURL() string
 	GetAWSRegion() string
 	GetAWSRole() string
+	GetSearchBackend() string
+	GetRepoCacheDir() string
 }
 
 type ConfigService struct{}
@@ -78,3 +81,19 @@ func (c *ConfigService) GetAWSRegion() string {
 }
 
 func (c *ConfigService) GetAWSRole() string { return os.Getenv("AWS_ROLE") }
+
+func (c *ConfigService) GetSearchBackend() string {
+	searchBackend := os.Getenv("SEARCH_BACKEND")
+	if searchBackend == "" {
+		return "api"
+	}
+	return searchBackend
+}
+
+func (c *ConfigService) GetRepoCacheDir() string {
+	repoCacheDir := os.Getenv("REPO_CACHE_DIR")
+	if repoCacheDir == "" {
+		return filepath.Join(os.TempDir(), "ofc-repo-cache")
+	}
+	return repoCacheDir
+}
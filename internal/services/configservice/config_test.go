@@ -54,3 +54,21 @@ func TestGetGithubToken(t *testing.T) {
 	cfg := configservice.NewConfigService()
 	assert.Equal(t, "123456", cfg.GetGithubToken())
 }
+
+func TestGetDefaultSearchBackend(t *testing.T) {
+	os.Unsetenv("SEARCH_BACKEND")
+	cfg := configservice.NewConfigService()
+	assert.Equal(t, "api", cfg.GetSearchBackend())
+}
+
+func TestGetSearchBackend(t *testing.T) {
+	os.Setenv("SEARCH_BACKEND", "clone")
+	cfg := configservice.NewConfigService()
+	assert.Equal(t, "clone", cfg.GetSearchBackend())
+}
+
+func TestGetRepoCacheDir(t *testing.T) {
+	os.Setenv("REPO_CACHE_DIR", "/tmp/ofc-cache")
+	cfg := configservice.NewConfigService()
+	assert.Equal(t, "/tmp/ofc-cache", cfg.GetRepoCacheDir())
+}
@@ -178,3 +178,31 @@ func (mr *MockConfigServiceInterfaceMockRecorder) GetPrometheusURL() *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrometheusURL", reflect.TypeOf((*MockConfigServiceInterface)(nil).GetPrometheusURL))
 }
+
+// GetRepoCacheDir mocks base method.
+func (m *MockConfigServiceInterface) GetRepoCacheDir() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepoCacheDir")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetRepoCacheDir indicates an expected call of GetRepoCacheDir.
+func (mr *MockConfigServiceInterfaceMockRecorder) GetRepoCacheDir() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepoCacheDir", reflect.TypeOf((*MockConfigServiceInterface)(nil).GetRepoCacheDir))
+}
+
+// GetSearchBackend mocks base method.
+func (m *MockConfigServiceInterface) GetSearchBackend() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSearchBackend")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetSearchBackend indicates an expected call of GetSearchBackend.
+func (mr *MockConfigServiceInterfaceMockRecorder) GetSearchBackend() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSearchBackend", reflect.TypeOf((*MockConfigServiceInterface)(nil).GetSearchBackend))
+}
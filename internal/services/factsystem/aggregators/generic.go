@@ -4,19 +4,32 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 
 	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
-	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/services/factsystem/registry"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/motain/of-catalog/internal/utils/eval"
 )
 
 type AggregatorInterface interface {
 	Combine(ctx context.Context, task *dtos.Task, deps []*dtos.Task) error
 }
 
-type Aggregator struct{}
+type Aggregator struct {
+	registry *registry.Registry
+}
+
+func NewAggregator(reg *registry.Registry) *Aggregator {
+	if reg == nil {
+		reg = registry.NewRegistry()
+	}
 
-func NewAggregator() *Aggregator {
-	return &Aggregator{}
+	ag := &Aggregator{registry: reg}
+	ag.registerBuiltins()
+
+	return ag
 }
 
 func (ag *Aggregator) Combine(ctx context.Context, task *dtos.Task, deps []*dtos.Task) error {
@@ -24,102 +37,387 @@ func (ag *Aggregator) Combine(ctx context.Context, task *dtos.Task, deps []*dtos
 		return nil
 	}
 
-	partials := make([]interface{}, len(deps))
-	for i, dep := range deps {
-		if dep.Result == nil {
+	for _, dep := range deps {
+		if dep.Result.IsNull() {
 			return errors.New("dependency result not provided")
 		}
-		combinedDepResult, depErr := ag.combineResults(task, dep.Result)
-		if depErr != nil {
-			return depErr
-		}
+	}
 
-		partials[i] = combinedDepResult
+	method, found := ag.registry.Method(task.Method)
+	if !found {
+		return errors.New("unknown method")
 	}
 
-	combinedPartialsResult, depErr := ag.combineResults(task, partials)
-	if depErr != nil {
-		return depErr
+	result, err := method.Combine(ctx, task, deps)
+	if err != nil {
+		return err
 	}
 
-	task.Result = combinedPartialsResult
+	task.Result = result
 	return nil
 }
 
-func (ag *Aggregator) combineResults(task *dtos.Task, results interface{}) (interface{}, error) {
-	switch dtos.TaskMethod(task.Method) {
-	case dtos.CountMethod:
-		return ag.count(results)
-	case dtos.SumMethod:
-		return ag.sum(results)
-	case dtos.AndMethod:
-		return ag.and(results)
-	case dtos.OrMethod:
-		return ag.or(results)
-	default:
-		return nil, errors.New("unknown method")
+// registerBuiltins registers the aggregation methods shipped with the fact system.
+func (ag *Aggregator) registerBuiltins() {
+	ag.registry.RegisterMethod(string(dtos.CountMethod), reduceMethod(ag.count))
+	ag.registry.RegisterMethod(string(dtos.SumMethod), reduceMethod(ag.sum))
+	ag.registry.RegisterMethod(string(dtos.AndMethod), reduceMethod(ag.and))
+	ag.registry.RegisterMethod(string(dtos.OrMethod), reduceMethod(ag.or))
+
+	ag.registry.RegisterMethod(string(dtos.MinMethod), valuesMethod(func(_ *dtos.Task, values []value.Value) (value.Value, error) {
+		return number(ag.min(values))
+	}))
+	ag.registry.RegisterMethod(string(dtos.MaxMethod), valuesMethod(func(_ *dtos.Task, values []value.Value) (value.Value, error) {
+		return number(ag.max(values))
+	}))
+	ag.registry.RegisterMethod(string(dtos.AvgMethod), valuesMethod(func(_ *dtos.Task, values []value.Value) (value.Value, error) {
+		return number(ag.avg(values))
+	}))
+	ag.registry.RegisterMethod(string(dtos.PercentileMethod), valuesMethod(func(task *dtos.Task, values []value.Value) (value.Value, error) {
+		return number(ag.percentile(values, task.Percentile))
+	}))
+	ag.registry.RegisterMethod(string(dtos.CountTrueMethod), valuesMethod(func(_ *dtos.Task, values []value.Value) (value.Value, error) {
+		return number(ag.countTrue(values))
+	}))
+
+	ag.registry.RegisterMethod(string(dtos.NotMethod), registry.MethodFunc(func(_ context.Context, _ *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		return ag.not(deps)
+	}))
+	ag.registry.RegisterMethod(string(dtos.RatioMethod), registry.MethodFunc(func(_ context.Context, _ *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		return number(ag.ratio(deps))
+	}))
+	ag.registry.RegisterMethod(string(dtos.WeightedSumMethod), registry.MethodFunc(func(_ context.Context, task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		return number(ag.weightedSum(deps, task.Weights))
+	}))
+	ag.registry.RegisterMethod(string(dtos.ExpressionMethod), registry.MethodFunc(func(_ context.Context, task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		return ag.evaluateExpression(task, deps)
+	}))
+}
+
+// reduceMethod adapts a method reducing a list of results to the registry. The result of every
+// dependency is reduced first, then the partial results are reduced again.
+func reduceMethod(reduce func(results value.Value) (value.Value, error)) registry.Method {
+	return registry.MethodFunc(func(_ context.Context, _ *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		partials := make([]value.Value, len(deps))
+		for i, dep := range deps {
+			combinedDepResult, depErr := reduce(dependencyResult(dep))
+			if depErr != nil {
+				return value.Null, depErr
+			}
+
+			partials[i] = combinedDepResult
+		}
+
+		return reduce(value.List(partials...))
+	})
+}
+
+// valuesMethod adapts a method describing the distribution of the values returned by all the
+// dependencies to the registry, lists are flattened so that every item weighs the same.
+func valuesMethod(combine func(task *dtos.Task, values []value.Value) (value.Value, error)) registry.Method {
+	return registry.MethodFunc(func(_ context.Context, task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		values := make([]value.Value, 0)
+		for _, dep := range deps {
+			values = append(values, dependencyResult(dep).Items()...)
+		}
+
+		return combine(task, values)
+	})
+}
+
+func number(n float64, err error) (value.Value, error) {
+	if err != nil {
+		return value.Null, err
+	}
+	return value.Number(n), nil
+}
+
+func (ag *Aggregator) count(results value.Value) (value.Value, error) {
+	items, listErr := results.List()
+	if listErr != nil {
+		return value.Null, fmt.Errorf("combineResult error for method \"count\": %s", listErr)
 	}
+
+	return value.Number(float64(len(items))), nil
 }
 
-func (ag *Aggregator) count(results interface{}) (float64, error) {
-	switch v := results.(type) {
-	case []interface{}:
-		return float64(len(v)), nil
-	case []string:
-		return float64(len(v)), nil
-	case []bool:
-		return float64(len(v)), nil
-	case []int:
-		return float64(len(v)), nil
-	default:
-		return 0, fmt.Errorf("unsupported type for count: %T", results)
+// dependencyResult returns the result combined for a dependency. The results of forEach facts (objects
+// keyed by item) are combined as the list of the result of every item in key order, lists flattened.
+// Objects returned by other facts are combined as they are.
+func dependencyResult(dep *dtos.Task) value.Value {
+	if dep.ForEach == nil {
+		return dep.Result
+	}
+
+	fields, objectErr := dep.Result.Object()
+	if objectErr != nil {
+		return dep.Result
 	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	values := make([]value.Value, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, fields[key].Items()...)
+	}
+	return value.List(values...)
 }
 
-func (ag *Aggregator) sum(results interface{}) (float64, error) {
-	values, castErr := utils.ToSlice[float64](results)
+// evaluateExpression evaluates the expression of the task, the result of every dependency is
+// available under its id with the characters not allowed in variable names replaced by underscores.
+func (ag *Aggregator) evaluateExpression(task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+	program, parseErr := eval.Parse(task.Expression)
+	if parseErr != nil {
+		return value.Null, fmt.Errorf("combineResult error for method \"expression\": %v", parseErr)
+	}
+
+	vars := make(map[string]interface{}, len(deps))
+	for _, dep := range deps {
+		vars[dtos.ExpressionVariable(dep.ID)] = dep.Result.Interface()
+	}
+
+	result, evalErr := program.Eval(vars)
+	if evalErr != nil {
+		return value.Null, fmt.Errorf("combineResult error for method \"expression\": %v", evalErr)
+	}
+
+	return value.Of(result)
+}
+
+// sum adds numbers, booleans count as 1 and 0.
+func (ag *Aggregator) sum(results value.Value) (value.Value, error) {
+	values, castErr := toNumbers(results.Items())
 	if castErr != nil {
-		return 0.0, fmt.Errorf("combineResult error for method \"sum\": %s", castErr)
+		return value.Null, fmt.Errorf("combineResult error for method \"sum\": %s", castErr)
 	}
 
 	sum := 0.0
 	for _, v := range values {
 		sum += v
 	}
-	return sum, nil
+	return value.Number(sum), nil
 }
 
-func (ag *Aggregator) and(results interface{}) (bool, error) {
-	if value, ok := results.(bool); ok {
-		return value, nil
+func (ag *Aggregator) and(results value.Value) (value.Value, error) {
+	values, castErr := toBools(results.Items())
+	if castErr != nil {
+		return value.Null, fmt.Errorf("combineResult error for method \"and\": %s", castErr)
+	}
+
+	for _, v := range values {
+		if !v {
+			return value.Bool(false), nil
+		}
 	}
+	return value.Bool(true), nil
+}
 
-	values, castErr := utils.ToSlice[bool](results)
+func (ag *Aggregator) or(results value.Value) (value.Value, error) {
+	values, castErr := toBools(results.Items())
 	if castErr != nil {
-		return false, fmt.Errorf("combineResult error for method \"and\": %s", castErr)
+		return value.Null, fmt.Errorf("combineResult error for method \"or\": %s", castErr)
 	}
 
 	for _, v := range values {
-		if !v {
-			return false, nil
+		if v {
+			return value.Bool(true), nil
 		}
 	}
-	return true, nil
+	return value.Bool(false), nil
+}
+
+func (ag *Aggregator) min(results []value.Value) (float64, error) {
+	values, castErr := numbers(dtos.MinMethod, results)
+	if castErr != nil {
+		return 0, castErr
+	}
+
+	return slices.Min(values), nil
 }
 
-func (ag *Aggregator) or(results interface{}) (bool, error) {
-	if value, ok := results.(bool); ok {
-		return value, nil
+func (ag *Aggregator) max(results []value.Value) (float64, error) {
+	values, castErr := numbers(dtos.MaxMethod, results)
+	if castErr != nil {
+		return 0, castErr
 	}
 
-	values, castErr := utils.ToSlice[bool](results)
+	return slices.Max(values), nil
+}
+
+func (ag *Aggregator) avg(results []value.Value) (float64, error) {
+	values, castErr := numbers(dtos.AvgMethod, results)
 	if castErr != nil {
-		return false, fmt.Errorf("combineResult error for method \"or\": %s", castErr)
+		return 0, castErr
 	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values)), nil
+}
+
+// percentile interpolates linearly between the closest ranks, percentile 50 is the median.
+func (ag *Aggregator) percentile(results []value.Value, percentile float64) (float64, error) {
+	if percentile < 0 || percentile > 100 {
+		return 0, fmt.Errorf("combineResult error for method \"percentile\": percentile must be between 0 and 100, got %v", percentile)
+	}
+
+	values, castErr := numbers(dtos.PercentileMethod, results)
+	if castErr != nil {
+		return 0, castErr
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower)), nil
+}
+
+func (ag *Aggregator) countTrue(results []value.Value) (float64, error) {
+	values, castErr := toBools(results)
+	if castErr != nil {
+		return 0, fmt.Errorf("combineResult error for method \"count_true\": %s", castErr)
+	}
+
+	count := 0.0
 	for _, v := range values {
 		if v {
-			return true, nil
+			count++
+		}
+	}
+	return count, nil
+}
+
+// not negates a boolean, or every item of a list of booleans.
+func (ag *Aggregator) not(deps []*dtos.Task) (value.Value, error) {
+	if len(deps) != 1 {
+		return value.Null, fmt.Errorf("combineResult error for method \"not\": expected 1 dependency, got %d", len(deps))
+	}
+
+	result := dependencyResult(deps[0])
+	if b, boolErr := result.Bool(); boolErr == nil {
+		return value.Bool(!b), nil
+	}
+
+	values, castErr := toBools(result.Items())
+	if castErr != nil {
+		return value.Null, fmt.Errorf("combineResult error for method \"not\": %s", castErr)
+	}
+
+	negated := make([]value.Value, len(values))
+	for i, v := range values {
+		negated[i] = value.Bool(!v)
+	}
+	return value.List(negated...), nil
+}
+
+// ratio divides the result of the first dependency by the result of the second one.
+func (ag *Aggregator) ratio(deps []*dtos.Task) (float64, error) {
+	if len(deps) != 2 {
+		return 0, fmt.Errorf("combineResult error for method \"ratio\": expected 2 dependencies, got %d", len(deps))
+	}
+
+	numerator, numeratorErr := dependencyValue(dtos.RatioMethod, deps[0])
+	if numeratorErr != nil {
+		return 0, numeratorErr
+	}
+
+	denominator, denominatorErr := dependencyValue(dtos.RatioMethod, deps[1])
+	if denominatorErr != nil {
+		return 0, denominatorErr
+	}
+
+	if denominator == 0 {
+		return 0, fmt.Errorf("combineResult error for method \"ratio\": denominator %s is 0", deps[1].ID)
+	}
+
+	return numerator / denominator, nil
+}
+
+// weightedSum sums the results of the dependencies multiplied by their weight, dependencies without
+// a weight count once.
+func (ag *Aggregator) weightedSum(deps []*dtos.Task, weights map[string]float64) (float64, error) {
+	for id := range weights {
+		if !slices.ContainsFunc(deps, func(dep *dtos.Task) bool { return dep.ID == id }) {
+			return 0, fmt.Errorf("combineResult error for method \"weighted_sum\": weight defined for unknown dependency %s", id)
+		}
+	}
+
+	sum := 0.0
+	for _, dep := range deps {
+		v, valueErr := dependencyValue(dtos.WeightedSumMethod, dep)
+		if valueErr != nil {
+			return 0, valueErr
+		}
+
+		weight, found := weights[dep.ID]
+		if !found {
+			weight = 1
+		}
+		sum += v * weight
+	}
+	return sum, nil
+}
+
+// numbers converts the values of a distribution method, an empty distribution has no min, max,
+// average or percentile.
+func numbers(method dtos.TaskMethod, results []value.Value) ([]float64, error) {
+	values, castErr := toNumbers(results)
+	if castErr != nil {
+		return nil, fmt.Errorf("combineResult error for method \"%s\": %s", method, castErr)
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("combineResult error for method \"%s\": no values to aggregate", method)
+	}
+
+	return values, nil
+}
+
+// dependencyValue reduces the result of a dependency to a number, a list contributes the sum of its
+// items (i.e. the number of true items for a list of booleans).
+func dependencyValue(method dtos.TaskMethod, dep *dtos.Task) (float64, error) {
+	values, castErr := toNumbers(dependencyResult(dep).Items())
+	if castErr != nil {
+		return 0, fmt.Errorf("combineResult error for method \"%s\": dependency %s: %s", method, dep.ID, castErr)
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum, nil
+}
+
+// toNumbers converts numbers, booleans and numeric strings.
+func toNumbers(values []value.Value) ([]float64, error) {
+	numbers := make([]float64, len(values))
+	for i, v := range values {
+		n, err := v.ToNumber()
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = n
+	}
+	return numbers, nil
+}
+
+func toBools(values []value.Value) ([]bool, error) {
+	bools := make([]bool, len(values))
+	for i, v := range values {
+		b, err := v.Bool()
+		if err != nil {
+			return nil, err
 		}
+		bools[i] = b
 	}
-	return false, nil
+	return bools, nil
 }
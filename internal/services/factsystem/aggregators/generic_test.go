@@ -0,0 +1,237 @@
+package aggregators_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/aggregators"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregator_Combine(t *testing.T) {
+	dep := func(id string, result interface{}) *dtos.Task {
+		return &dtos.Task{ID: id, Result: value.MustOf(result)}
+	}
+	forEachDep := func(id string, result interface{}) *dtos.Task {
+		return &dtos.Task{ID: id, ForEach: &dtos.TaskForEach{Dependency: "services"}, Result: value.MustOf(result)}
+	}
+
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		deps        []*dtos.Task
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "sum coerces jsonpath results",
+			task:     dtos.Task{Method: "sum"},
+			deps:     []*dtos.Task{dep("a", []interface{}{1, 2.5}), dep("b", []interface{}{"3"})},
+			expected: 6.5,
+		},
+		{
+			name:     "count",
+			task:     dtos.Task{Method: "count"},
+			deps:     []*dtos.Task{dep("a", []interface{}{"x", "y"}), dep("b", []string{"z"})},
+			expected: 2.0,
+		},
+		{
+			name:     "count with forEach results",
+			task:     dtos.Task{Method: "count"},
+			deps:     []*dtos.Task{forEachDep("a", map[string]interface{}{"api": []int{3}}), dep("b", []string{"z"})},
+			expected: 2.0,
+		},
+		{
+			name:        "count of an object which is not a forEach result",
+			task:        dtos.Task{Method: "count"},
+			deps:        []*dtos.Task{dep("a", map[string]interface{}{"api": 3, "worker": 1})},
+			expectedErr: `combineResult error for method "count"`,
+		},
+		{
+			name:     "sum forEach results",
+			task:     dtos.Task{Method: "sum"},
+			deps:     []*dtos.Task{forEachDep("a", map[string]interface{}{"api": []int{3}, "worker": 1})},
+			expected: 4.0,
+		},
+		{
+			name:        "sum of an object which is not a forEach result",
+			task:        dtos.Task{Method: "sum"},
+			deps:        []*dtos.Task{dep("a", map[string]interface{}{"api": 3, "worker": 1})},
+			expectedErr: `combineResult error for method "sum"`,
+		},
+		{
+			name:     "and",
+			task:     dtos.Task{Method: "and"},
+			deps:     []*dtos.Task{dep("a", true), dep("b", []bool{true, false})},
+			expected: false,
+		},
+		{
+			name:     "min across dependencies",
+			task:     dtos.Task{Method: "min"},
+			deps:     []*dtos.Task{dep("a", []interface{}{3.0, 6.0}), dep("b", 2)},
+			expected: 2.0,
+		},
+		{
+			name:     "max",
+			task:     dtos.Task{Method: "max"},
+			deps:     []*dtos.Task{dep("a", []interface{}{3.0, 6.0}), dep("b", "2")},
+			expected: 6.0,
+		},
+		{
+			name:     "avg weighs every item the same",
+			task:     dtos.Task{Method: "avg"},
+			deps:     []*dtos.Task{dep("a", []interface{}{2, 4, 6}), dep("b", 8.0)},
+			expected: 5.0,
+		},
+		{
+			name:        "avg of empty input",
+			task:        dtos.Task{Method: "avg"},
+			deps:        []*dtos.Task{dep("a", []interface{}{})},
+			expectedErr: `combineResult error for method "avg": no values to aggregate`,
+		},
+		{
+			name:        "avg of non numeric values",
+			task:        dtos.Task{Method: "avg"},
+			deps:        []*dtos.Task{dep("a", []interface{}{"three"})},
+			expectedErr: `invalid number "three"`,
+		},
+		{
+			name:     "median",
+			task:     dtos.Task{Method: "percentile", Percentile: 50},
+			deps:     []*dtos.Task{dep("a", []interface{}{5, 1, 3, 2})},
+			expected: 2.5,
+		},
+		{
+			name:     "p90 interpolates between ranks",
+			task:     dtos.Task{Method: "percentile", Percentile: 90},
+			deps:     []*dtos.Task{dep("a", []interface{}{10, 20, 30, 40, 50})},
+			expected: 46.0,
+		},
+		{
+			name:     "percentile of a single value",
+			task:     dtos.Task{Method: "percentile", Percentile: 99},
+			deps:     []*dtos.Task{dep("a", 7.0)},
+			expected: 7.0,
+		},
+		{
+			name:        "percentile out of range",
+			task:        dtos.Task{Method: "percentile", Percentile: 101},
+			deps:        []*dtos.Task{dep("a", 7.0)},
+			expectedErr: "percentile must be between 0 and 100",
+		},
+		{
+			name:     "count_true",
+			task:     dtos.Task{Method: "count_true"},
+			deps:     []*dtos.Task{dep("a", []bool{true, false, true}), dep("b", true)},
+			expected: 3.0,
+		},
+		{
+			name:     "count_true of empty input",
+			task:     dtos.Task{Method: "count_true"},
+			deps:     []*dtos.Task{dep("a", []bool{})},
+			expected: 0.0,
+		},
+		{
+			name:        "count_true of non boolean values",
+			task:        dtos.Task{Method: "count_true"},
+			deps:        []*dtos.Task{dep("a", []interface{}{1.0})},
+			expectedErr: `combineResult error for method "count_true"`,
+		},
+		{
+			name:     "not",
+			task:     dtos.Task{Method: "not"},
+			deps:     []*dtos.Task{dep("a", false)},
+			expected: true,
+		},
+		{
+			name:     "not on a list",
+			task:     dtos.Task{Method: "not"},
+			deps:     []*dtos.Task{dep("a", []bool{true, false})},
+			expected: []bool{false, true},
+		},
+		{
+			name:        "not with too many dependencies",
+			task:        dtos.Task{Method: "not"},
+			deps:        []*dtos.Task{dep("a", true), dep("b", true)},
+			expectedErr: "expected 1 dependency, got 2",
+		},
+		{
+			name:     "ratio of passing workflows",
+			task:     dtos.Task{Method: "ratio"},
+			deps:     []*dtos.Task{dep("passing", []bool{true, false, true}), dep("workflows", []interface{}{4})},
+			expected: 0.5,
+		},
+		{
+			name:        "ratio with a zero denominator",
+			task:        dtos.Task{Method: "ratio"},
+			deps:        []*dtos.Task{dep("passing", 0.0), dep("workflows", 0.0)},
+			expectedErr: "denominator workflows is 0",
+		},
+		{
+			name:        "ratio with a single dependency",
+			task:        dtos.Task{Method: "ratio"},
+			deps:        []*dtos.Task{dep("passing", 1.0)},
+			expectedErr: "expected 2 dependencies, got 1",
+		},
+		{
+			name:     "weighted_sum",
+			task:     dtos.Task{Method: "weighted_sum", Weights: map[string]float64{"has-runbook": 0.5, "has-slo": 2}},
+			deps:     []*dtos.Task{dep("has-runbook", true), dep("has-slo", true), dep("has-owner", false), dep("alerts", []interface{}{1, 2})},
+			expected: 5.5,
+		},
+		{
+			name:        "weighted_sum with a weight for an unknown dependency",
+			task:        dtos.Task{Method: "weighted_sum", Weights: map[string]float64{"has-runbok": 0.5}},
+			deps:        []*dtos.Task{dep("has-runbook", true)},
+			expectedErr: "weight defined for unknown dependency has-runbok",
+		},
+		{
+			name:     "expression",
+			task:     dtos.Task{Method: "expression", Expression: "(has_runbook + alerts) / total * 100"},
+			deps:     []*dtos.Task{dep("has-runbook", true), dep("alerts", []interface{}{"a", "b", "c"}), dep("total", 8.0)},
+			expected: 50.0,
+		},
+		{
+			name:     "boolean expression",
+			task:     dtos.Task{Method: "expression", Expression: "replicas >= 2 and not public"},
+			deps:     []*dtos.Task{dep("replicas", 3), dep("public", false)},
+			expected: true,
+		},
+		{
+			name:        "expression failing at runtime",
+			task:        dtos.Task{Method: "expression", Expression: "a / b"},
+			deps:        []*dtos.Task{dep("a", 1.0), dep("b", 0.0)},
+			expectedErr: `combineResult error for method "expression": division by zero`,
+		},
+		{
+			name:        "unknown method",
+			task:        dtos.Task{Method: "median"},
+			deps:        []*dtos.Task{dep("a", 1.0)},
+			expectedErr: "unknown method",
+		},
+		{
+			name:        "missing dependency result",
+			task:        dtos.Task{Method: "max"},
+			deps:        []*dtos.Task{dep("a", nil)},
+			expectedErr: "dependency result not provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.Type = "aggregate"
+
+			err := aggregators.NewAggregator(nil).Combine(context.Background(), &task, tt.deps)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package dtos
+
+import "context"
+
+type componentContextKey struct{}
+
+// WithComponent returns a copy of ctx carrying the JSON definition of the component the facts are computed for.
+// It is read by the facts of source component.
+func WithComponent(ctx context.Context, component []byte) context.Context {
+	return context.WithValue(ctx, componentContextKey{}, component)
+}
+
+// ComponentFromContext returns the JSON definition of the component stored with WithComponent.
+func ComponentFromContext(ctx context.Context) ([]byte, bool) {
+	component, ok := ctx.Value(componentContextKey{}).([]byte)
+	return component, ok
+}
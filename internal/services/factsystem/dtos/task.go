@@ -1,5 +1,11 @@
 package dtos
 
+import (
+	"reflect"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+)
+
 type TaskType string
 
 const (
@@ -16,11 +22,25 @@ const (
 	NotEmptyRule TaskRule = "notempty"
 	SearchRule   TaskRule = "search"
 
+	VulnerabilitiesRule TaskRule = "vulnerabilities"
+
 	// Validation rules
 	DepsMatchRule  TaskRule = "deps_match"
 	UniqueRule     TaskRule = "unique"
 	RegexMatchRule TaskRule = "regex_match"
 	FormulaRule    TaskRule = "formula"
+	SubsetOfRule   TaskRule = "subset_of"
+	IntersectsRule TaskRule = "intersects"
+	SemverRule     TaskRule = "semver"
+	JSONSchemaRule TaskRule = "json_schema"
+	AgeRule        TaskRule = "age"
+)
+
+type TaskMatchMode string
+
+const (
+	OrderedMatchMode TaskMatchMode = "ordered"
+	SetMatchMode     TaskMatchMode = "set"
 )
 
 type TaskSource string
@@ -29,20 +49,137 @@ const (
 	GitHubTaskSource     TaskSource = "github"
 	JSONAPITaskSource    TaskSource = "jsonapi"
 	PrometheusTaskSource TaskSource = "prometheus"
+	LocalTaskSource      TaskSource = "local"
+	AWSTaskSource        TaskSource = "aws"
+	CompassTaskSource    TaskSource = "compass"
+	ComponentTaskSource  TaskSource = "component"
+	ExecTaskSource       TaskSource = "exec"
+)
+
+type TaskSearchBackend string
+
+const (
+	APISearchBackend   TaskSearchBackend = "api"
+	CloneSearchBackend TaskSearchBackend = "clone"
+)
+
+type TaskSearchMode string
+
+const (
+	LiteralSearchMode TaskSearchMode = "literal"
+	RegexSearchMode   TaskSearchMode = "regex"
 )
 
 type TaskMethod string
 
 const (
-	CountMethod TaskMethod = "count"
-	SumMethod   TaskMethod = "sum"
-	AndMethod   TaskMethod = "and"
-	OrMethod    TaskMethod = "or"
+	CountMethod       TaskMethod = "count"
+	SumMethod         TaskMethod = "sum"
+	AndMethod         TaskMethod = "and"
+	OrMethod          TaskMethod = "or"
+	MinMethod         TaskMethod = "min"
+	MaxMethod         TaskMethod = "max"
+	AvgMethod         TaskMethod = "avg"
+	PercentileMethod  TaskMethod = "percentile"
+	CountTrueMethod   TaskMethod = "count_true"
+	NotMethod         TaskMethod = "not"
+	RatioMethod       TaskMethod = "ratio"
+	WeightedSumMethod TaskMethod = "weighted_sum"
+	ExpressionMethod  TaskMethod = "expression"
+)
+
+type TaskAuthType string
+
+const (
+	HeaderAuthType TaskAuthType = "header"
+	BasicAuthType  TaskAuthType = "basic"
+	BearerAuthType TaskAuthType = "bearer"
 )
 
+// TaskAuth defines how JSON API requests are authenticated.
+// Secrets are never stored in the fact, the *Var fields name the environment variables holding them.
+// Type defaults to "header", sending the value of TokenVar in Header.
 type TaskAuth struct {
-	Header   string `yaml:"header,omitempty" json:"header,omitempty"`
-	TokenVar string `yaml:"tokenVar,omitempty" json:"tokenVar,omitempty"`
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`
+	Header      string `yaml:"header,omitempty" json:"header,omitempty"`
+	TokenVar    string `yaml:"tokenVar,omitempty" json:"tokenVar,omitempty"`
+	UsernameVar string `yaml:"usernameVar,omitempty" json:"usernameVar,omitempty"`
+	PasswordVar string `yaml:"passwordVar,omitempty" json:"passwordVar,omitempty"`
+}
+
+// TaskRetry defines how JSON API requests failing with 429 or 5xx status codes are retried.
+// Attempts is the total number of requests (default 3), Backoff the initial delay doubled on every
+// attempt (default 1s) and MaxBackoff the longest delay (default 30s). Retry-After headers take precedence.
+// Without retry, only GET and HEAD requests are retried with the defaults.
+type TaskRetry struct {
+	Attempts   int    `yaml:"attempts,omitempty" json:"attempts,omitempty"`
+	Backoff    string `yaml:"backoff,omitempty" json:"backoff,omitempty"`
+	MaxBackoff string `yaml:"maxBackoff,omitempty" json:"maxBackoff,omitempty"`
+}
+
+type TaskPaginationType string
+
+const (
+	LinkPagination   TaskPaginationType = "link"
+	CursorPagination TaskPaginationType = "cursor"
+	PagePagination   TaskPaginationType = "page"
+)
+
+// TaskPagination defines how to walk a paginated JSON API. The items of every page are concatenated
+// into a single JSON array before the rule is applied.
+//   - link: follows the rel="next" URL of the Link header
+//   - cursor: reads the next cursor with CursorPath and sends it as the CursorParam query parameter
+//   - page: increments the PageParam query parameter until a page has no items, starting at StartPage
+//     (default 1, 0 for zero-indexed APIs)
+//
+// Pages beyond MaxPages (default 10) fail the fact rather than returning a partial listing.
+type TaskPagination struct {
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`
+	ItemsPath   string `yaml:"itemsPath,omitempty" json:"itemsPath,omitempty"`
+	CursorPath  string `yaml:"cursorPath,omitempty" json:"cursorPath,omitempty"`
+	CursorParam string `yaml:"cursorParam,omitempty" json:"cursorParam,omitempty"`
+	PageParam   string `yaml:"pageParam,omitempty" json:"pageParam,omitempty"`
+	StartPage   *int   `yaml:"startPage,omitempty" json:"startPage,omitempty"`
+	MaxPages    int    `yaml:"maxPages,omitempty" json:"maxPages,omitempty"`
+}
+
+// TaskRangeQuery defines the boundaries of a Prometheus range query.
+// Start and End accept relative durations (e.g. "-7d"), RFC3339 or unix timestamps, Step a Prometheus duration.
+type TaskRangeQuery struct {
+	Start string `yaml:"start,omitempty" json:"start,omitempty"`
+	End   string `yaml:"end,omitempty" json:"end,omitempty"`
+	Step  string `yaml:"step,omitempty" json:"step,omitempty"`
+}
+
+// TaskExec defines the plugin run by exec facts. The plugin reads a JSON request on stdin and writes a
+// JSON response on stdout, the protocol is documented with the exec source. Timeout is a Prometheus
+// duration (default 30s) and Env the names of the environment variables passed to the plugin in addition to PATH.
+type TaskExec struct {
+	Command string   `yaml:"command,omitempty" json:"command,omitempty"`
+	Args    []string `yaml:"args,omitempty" json:"args,omitempty"`
+	Timeout string   `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	Env     []string `yaml:"env,omitempty" json:"env,omitempty"`
+}
+
+// DefaultForEachVariable is the name of the item of forEach facts not declaring one.
+const DefaultForEachVariable = "item"
+
+// TaskForEach runs an extract fact once per item of the result of one of its dependencies.
+// The item is available to the string fields of the fact as {{As}} (default {{item}}), Concurrency
+// bounds the items processed at the same time (default 1). The result of the fact is an object
+// with the result of every item, keyed by the item.
+type TaskForEach struct {
+	Dependency  string `yaml:"dependency,omitempty" json:"dependency,omitempty"`
+	As          string `yaml:"as,omitempty" json:"as,omitempty"`
+	Concurrency int    `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+}
+
+// Variable returns the name under which the item is available to templates.
+func (f *TaskForEach) Variable() string {
+	if f.As == "" {
+		return DefaultForEachVariable
+	}
+	return f.As
 }
 
 type TaskResult struct {
@@ -55,8 +192,13 @@ type Task struct {
 	Type      string   `yaml:"type,omitempty" json:"type,omitempty"`
 	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
 
+	// Reference to a fact template (name or name@version) and its parameters, expanded when metrics are bound
+	Template string            `yaml:"template,omitempty" json:"template,omitempty"`
+	Params   map[string]string `yaml:"params,omitempty" json:"params,omitempty"`
+
 	// Extract related fields
-	Source string `yaml:"source,omitempty" json:"source,omitempty"`
+	Source  string       `yaml:"source,omitempty" json:"source,omitempty"`
+	ForEach *TaskForEach `yaml:"forEach,omitempty" json:"forEach,omitempty"`
 
 	// Extract related fields for REST API calls
 	URI             string    `yaml:"uri,omitempty" json:"uri,omitempty"`
@@ -64,20 +206,60 @@ type Task struct {
 	Auth            *TaskAuth `yaml:"auth,omitempty" json:"auth,omitempty"`
 	PrometheusQuery string    `yaml:"prometheusQuery,omitempty" json:"prometheusQuery,omitempty"`
 
+	HTTPMethod     string            `yaml:"httpMethod,omitempty" json:"httpMethod,omitempty"`
+	Body           string            `yaml:"body,omitempty" json:"body,omitempty"`
+	Headers        map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	QueryParams    map[string]string `yaml:"queryParams,omitempty" json:"queryParams,omitempty"`
+	ExpectedStatus []int             `yaml:"expectedStatus,omitempty" json:"expectedStatus,omitempty"`
+	Pagination     *TaskPagination   `yaml:"pagination,omitempty" json:"pagination,omitempty"`
+	Retry          *TaskRetry        `yaml:"retry,omitempty" json:"retry,omitempty"`
+
+	// Extract related fields for Prometheus queries
+	Datasource string          `yaml:"datasource,omitempty" json:"datasource,omitempty"`
+	RangeQuery *TaskRangeQuery `yaml:"rangeQuery,omitempty" json:"rangeQuery,omitempty"`
+
 	// Extract related fields for GitHub API calls
-	Repo         string `yaml:"repo,omitempty" json:"repo,omitempty"`
-	FilePath     string `yaml:"filePath,omitempty"`
-	SearchString string `yaml:"searchString,omitempty" json:"searchString,omitempty"`
+	Repo          string `yaml:"repo,omitempty" json:"repo,omitempty"`
+	Branch        string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	FilePath      string `yaml:"filePath,omitempty"`
+	SearchString  string `yaml:"searchString,omitempty" json:"searchString,omitempty"`
+	SearchBackend string `yaml:"searchBackend,omitempty" json:"searchBackend,omitempty"`
+	SearchMode    string `yaml:"searchMode,omitempty" json:"searchMode,omitempty"`
+
+	// Extract related fields for local directories, FilePath and the search fields are shared with GitHub
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// Extract related fields for AWS resources
+	ResourceType string `yaml:"resourceType,omitempty" json:"resourceType,omitempty"`
+	ResourceID   string `yaml:"resourceId,omitempty" json:"resourceId,omitempty"`
+
+	// Extract related fields for Compass, defaults to the id of the bound component
+	ComponentID string `yaml:"componentId,omitempty" json:"componentId,omitempty"`
+
+	// Extract related fields for plugins
+	Exec *TaskExec `yaml:"exec,omitempty" json:"exec,omitempty"`
+
+	// Extract related fields for vulnerability and SBOM reports read with the vulnerabilities rule
+	ReportFormat string `yaml:"reportFormat,omitempty" json:"reportFormat,omitempty"`
+	Severity     string `yaml:"severity,omitempty" json:"severity,omitempty"`
 
 	// Validate related fields
-	Rule    string `yaml:"rule,omitempty" json:"rule,omitempty"`
-	Pattern string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Rule      string `yaml:"rule,omitempty" json:"rule,omitempty"`
+	Pattern   string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	MatchMode string `yaml:"matchMode,omitempty" json:"matchMode,omitempty"` // How deps_match compares lists, ordered (default) or set
+
+	Schema     map[string]interface{} `yaml:"schema,omitempty" json:"schema,omitempty"`         // Inline JSON schema for json_schema
+	SchemaFile string                 `yaml:"schemaFile,omitempty" json:"schemaFile,omitempty"` // JSON or YAML schema file for json_schema
+	MaxAge     string                 `yaml:"maxAge,omitempty" json:"maxAge,omitempty"`         // Maximum age for age, e.g. 90d
 
 	// Aggregate related fields
-	Method string `yaml:"method,omitempty" json:"method,omitempty"`
+	Method     string             `yaml:"method,omitempty" json:"method,omitempty"`
+	Weights    map[string]float64 `yaml:"weights,omitempty" json:"weights,omitempty"`       // Weight of each dependency for weighted_sum, defaults to 1
+	Percentile float64            `yaml:"percentile,omitempty" json:"percentile,omitempty"` // Percentile between 0 and 100 for percentile
+	Expression string             `yaml:"expression,omitempty" json:"expression,omitempty"` // Expression over the dependency results for expression
 
 	// Run related fields
-	Result       interface{}     `yaml:"-" json:"-"`
+	Result       value.Value     `yaml:"-" json:"-"`
 	Dependencies []*Task         `yaml:"-" json:"-"` // List of tasks this task depends on
 	DoneCh       chan TaskResult `yaml:"-" json:"-"` // Channel to signal task completion
 }
@@ -90,18 +272,47 @@ func (t1 *Task) IsEqual(t2 *Task) bool {
 	return t1.ID == t2.ID &&
 		t1.Name == t2.Name &&
 		t1.Type == t2.Type &&
+		t1.Template == t2.Template &&
+		reflect.DeepEqual(t1.Params, t2.Params) &&
 		t1.Source == t2.Source &&
+		reflect.DeepEqual(t1.ForEach, t2.ForEach) &&
 		t1.URI == t2.URI &&
 		t1.JSONPath == t2.JSONPath &&
-		t1.Auth == t2.Auth &&
+		reflect.DeepEqual(t1.Auth, t2.Auth) &&
+		t1.HTTPMethod == t2.HTTPMethod &&
+		t1.Body == t2.Body &&
+		reflect.DeepEqual(t1.Headers, t2.Headers) &&
+		reflect.DeepEqual(t1.QueryParams, t2.QueryParams) &&
+		reflect.DeepEqual(t1.ExpectedStatus, t2.ExpectedStatus) &&
+		reflect.DeepEqual(t1.Pagination, t2.Pagination) &&
+		reflect.DeepEqual(t1.Retry, t2.Retry) &&
 		t1.Repo == t2.Repo &&
 		t1.FilePath == t2.FilePath &&
+		t1.Path == t2.Path &&
+		t1.ResourceType == t2.ResourceType &&
+		t1.ResourceID == t2.ResourceID &&
+		t1.ComponentID == t2.ComponentID &&
+		reflect.DeepEqual(t1.Exec, t2.Exec) &&
+		t1.ReportFormat == t2.ReportFormat &&
+		t1.Severity == t2.Severity &&
 		t1.Rule == t2.Rule &&
 		t1.Pattern == t2.Pattern &&
+		t1.MatchMode == t2.MatchMode &&
+		reflect.DeepEqual(t1.Schema, t2.Schema) &&
+		t1.SchemaFile == t2.SchemaFile &&
+		t1.MaxAge == t2.MaxAge &&
 		t1.Method == t2.Method &&
-		t1.Result == t2.Result &&
+		reflect.DeepEqual(t1.Weights, t2.Weights) &&
+		t1.Percentile == t2.Percentile &&
+		t1.Expression == t2.Expression &&
+		t1.Result.Equal(t2.Result) &&
 		t1.SearchString == t2.SearchString &&
+		t1.Branch == t2.Branch &&
+		t1.SearchBackend == t2.SearchBackend &&
+		t1.SearchMode == t2.SearchMode &&
 		t1.PrometheusQuery == t2.PrometheusQuery &&
+		t1.Datasource == t2.Datasource &&
+		reflect.DeepEqual(t1.RangeQuery, t2.RangeQuery) &&
 		t1.IsDependsOnEquals(t2.DependsOn)
 }
 
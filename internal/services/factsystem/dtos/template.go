@@ -0,0 +1,66 @@
+package dtos
+
+// WithTemplates returns a copy of the task whose runtime string fields (URIs, paths, queries, bodies,
+// headers, query parameters, identifiers and plugin arguments) are rendered with render.
+// It is used to substitute the item of forEach facts, the task itself is left unchanged.
+func (t *Task) WithTemplates(render func(string) (string, error)) (*Task, error) {
+	rendered := *t
+
+	fields := []*string{
+		&rendered.URI,
+		&rendered.JSONPath,
+		&rendered.PrometheusQuery,
+		&rendered.Body,
+		&rendered.Repo,
+		&rendered.Branch,
+		&rendered.FilePath,
+		&rendered.SearchString,
+		&rendered.Path,
+		&rendered.ResourceID,
+		&rendered.ComponentID,
+	}
+	for _, field := range fields {
+		renderedField, err := render(*field)
+		if err != nil {
+			return nil, err
+		}
+		*field = renderedField
+	}
+
+	var err error
+	if rendered.Headers, err = renderMap(t.Headers, render); err != nil {
+		return nil, err
+	}
+	if rendered.QueryParams, err = renderMap(t.QueryParams, render); err != nil {
+		return nil, err
+	}
+
+	if t.Exec != nil {
+		exec := *t.Exec
+		exec.Args = make([]string, len(t.Exec.Args))
+		for i, arg := range t.Exec.Args {
+			if exec.Args[i], err = render(arg); err != nil {
+				return nil, err
+			}
+		}
+		rendered.Exec = &exec
+	}
+
+	return &rendered, nil
+}
+
+func renderMap(values map[string]string, render func(string) (string, error)) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	rendered := make(map[string]string, len(values))
+	for name, value := range values {
+		renderedValue, err := render(value)
+		if err != nil {
+			return nil, err
+		}
+		rendered[name] = renderedValue
+	}
+	return rendered, nil
+}
@@ -0,0 +1,169 @@
+package dtos
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/utils/eval"
+	"github.com/prometheus/common/model"
+)
+
+var nonVariableChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+var forEachVariable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// metricPlaceholders matches the ${...} placeholders replaced when metrics are bound to components.
+var metricPlaceholders = regexp.MustCompile(`\$\{.*?\}`)
+
+// ExpressionVariable returns the name under which the result of a dependency is available to
+// expressions, characters not allowed in variable names are replaced with underscores
+// (e.g. has-runbook becomes has_runbook).
+func ExpressionVariable(id string) string {
+	return nonVariableChars.ReplaceAllString(id, "_")
+}
+
+// Validate checks the definition of the task before it is applied, so that errors surface when
+// the metric is validated rather than when it is computed.
+func (t *Task) Validate() error {
+	if t.ForEach != nil {
+		if err := t.validateForEach(); err != nil {
+			return err
+		}
+	}
+
+	if TaskType(t.Type) == AggregateType && TaskMethod(t.Method) == ExpressionMethod {
+		return t.validateExpression()
+	}
+
+	if TaskType(t.Type) == ExtractType && TaskSource(t.Source) == ExecTaskSource {
+		return t.validateExec()
+	}
+
+	if TaskType(t.Type) != ValidateType {
+		return nil
+	}
+
+	switch TaskRule(t.Rule) {
+	case FormulaRule:
+		return t.validateFormula()
+	case SemverRule:
+		if _, err := semver.NewConstraint(metricPlaceholders.ReplaceAllString(t.Pattern, "0")); err != nil {
+			return fmt.Errorf("invalid semver constraint %q: %v", t.Pattern, err)
+		}
+	case JSONSchemaRule:
+		// Schema files are resolved where the metric is computed, only inline schemas can be checked
+		if t.SchemaFile == "" || t.Schema != nil {
+			if _, err := utils.CompileSchema(t.Schema, t.SchemaFile); err != nil {
+				return err
+			}
+		}
+	case AgeRule:
+		if _, err := model.ParseDuration(t.MaxAge); err != nil {
+			return fmt.Errorf("invalid max age %q: %v", t.MaxAge, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Task) validateFormula() error {
+	// Placeholders are only known once the metric is bound, any literal keeps the syntax valid
+	pattern := metricPlaceholders.ReplaceAllString(t.Pattern, "0")
+
+	formula, parseErr := eval.Formula(pattern)
+	if parseErr != nil {
+		return fmt.Errorf("invalid formula %q: %v", t.Pattern, parseErr)
+	}
+
+	for _, variable := range formula.Variables() {
+		if variable != eval.FormulaVariable {
+			return fmt.Errorf("formula %q references %s, only %s is available", t.Pattern, variable, eval.FormulaVariable)
+		}
+	}
+
+	return nil
+}
+
+func (t *Task) validateExec() error {
+	if t.Exec == nil || t.Exec.Command == "" {
+		return fmt.Errorf("source %s requires exec.command", ExecTaskSource)
+	}
+
+	if t.Exec.Timeout != "" {
+		if _, err := model.ParseDuration(t.Exec.Timeout); err != nil {
+			return fmt.Errorf("invalid exec timeout %q: %v", t.Exec.Timeout, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Task) validateForEach() error {
+	if TaskType(t.Type) != ExtractType {
+		return fmt.Errorf("forEach is only supported by %s facts", ExtractType)
+	}
+
+	if !t.dependsOn(t.ForEach.Dependency) {
+		return fmt.Errorf("forEach dependency %q is not a dependency", t.ForEach.Dependency)
+	}
+
+	variable := t.ForEach.Variable()
+	if !forEachVariable.MatchString(variable) {
+		return fmt.Errorf("invalid forEach variable %q", variable)
+	}
+
+	if t.ForEach.Concurrency < 0 {
+		return fmt.Errorf("invalid forEach concurrency %d", t.ForEach.Concurrency)
+	}
+
+	_, err := t.WithTemplates(func(field string) (string, error) {
+		for _, name := range utils.TemplateVariables(field) {
+			if name != variable {
+				return "", fmt.Errorf("template {{%s}} references an unknown variable, only %s is available", name, variable)
+			}
+		}
+		return field, nil
+	})
+
+	return err
+}
+
+func (t *Task) dependsOn(id string) bool {
+	for _, dependsOn := range t.DependsOn {
+		if id != "" && dependsOn == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Task) validateExpression() error {
+	if t.Expression == "" {
+		return fmt.Errorf("method %s requires an expression", ExpressionMethod)
+	}
+
+	program, parseErr := eval.Parse(t.Expression)
+	if parseErr != nil {
+		return fmt.Errorf("invalid expression %q: %v", t.Expression, parseErr)
+	}
+
+	// dependencies whose ids only differ by characters replaced in variable names would shadow each other
+	dependencies := make(map[string]string, len(t.DependsOn))
+	for _, dependsOn := range t.DependsOn {
+		variable := ExpressionVariable(dependsOn)
+		if other, found := dependencies[variable]; found && other != dependsOn {
+			return fmt.Errorf("dependencies %s and %s are both available to the expression as %s, rename one of them", other, dependsOn, variable)
+		}
+		dependencies[variable] = dependsOn
+	}
+
+	for _, variable := range program.Variables() {
+		if _, found := dependencies[variable]; !found {
+			return fmt.Errorf("expression %q references %s, which is not a dependency", t.Expression, variable)
+		}
+	}
+
+	return nil
+}
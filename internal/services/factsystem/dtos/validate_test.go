@@ -0,0 +1,142 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		expectedErr string
+	}{
+		{
+			name: "valid expression",
+			task: dtos.Task{Type: "aggregate", Method: "expression", DependsOn: []string{"has-runbook", "total"}, Expression: "has_runbook / total"},
+		},
+		{
+			name:        "missing expression",
+			task:        dtos.Task{Type: "aggregate", Method: "expression", DependsOn: []string{"a"}},
+			expectedErr: "method expression requires an expression",
+		},
+		{
+			name:        "invalid expression",
+			task:        dtos.Task{Type: "aggregate", Method: "expression", DependsOn: []string{"a"}, Expression: "a +"},
+			expectedErr: `invalid expression "a +": syntax error at position 3: unexpected end of expression`,
+		},
+		{
+			name:        "expression dependencies sharing a variable",
+			task:        dtos.Task{Type: "aggregate", Method: "expression", DependsOn: []string{"has-runbook", "has_runbook"}, Expression: "has_runbook"},
+			expectedErr: "dependencies has-runbook and has_runbook are both available to the expression as has_runbook, rename one of them",
+		},
+		{
+			name:        "expression referencing an unknown fact",
+			task:        dtos.Task{Type: "aggregate", Method: "expression", DependsOn: []string{"a"}, Expression: "a + b"},
+			expectedErr: `expression "a + b" references b, which is not a dependency`,
+		},
+		{
+			name: "exec plugin",
+			task: dtos.Task{Type: "extract", Source: "exec", Exec: &dtos.TaskExec{Command: "./plugins/check-dockerfile.py", Timeout: "1m"}},
+		},
+		{
+			name:        "exec without command",
+			task:        dtos.Task{Type: "extract", Source: "exec"},
+			expectedErr: "source exec requires exec.command",
+		},
+		{
+			name:        "exec with an invalid timeout",
+			task:        dtos.Task{Type: "extract", Source: "exec", Exec: &dtos.TaskExec{Command: "check", Timeout: "soon"}},
+			expectedErr: `invalid exec timeout "soon"`,
+		},
+		{
+			name: "partial formula",
+			task: dtos.Task{Type: "validate", Rule: "formula", Pattern: ">= -1"},
+		},
+		{
+			name: "formula with placeholders",
+			task: dtos.Task{Type: "validate", Rule: "formula", Pattern: `value == "${Metadata.Name}" or value == ${Spec.Fields.tier}`},
+		},
+		{
+			name:        "invalid formula",
+			task:        dtos.Task{Type: "validate", Rule: "formula", Pattern: "> > 1"},
+			expectedErr: `invalid formula "> > 1": syntax error at position 2: unexpected ">"`,
+		},
+		{
+			name:        "formula referencing another variable",
+			task:        dtos.Task{Type: "validate", Rule: "formula", Pattern: "replicas > 1"},
+			expectedErr: `formula "replicas > 1" references replicas, only value is available`,
+		},
+		{
+			name: "semver constraint with placeholders",
+			task: dtos.Task{Type: "validate", Rule: "semver", Pattern: ">= ${Spec.Fields.min_go_version}"},
+		},
+		{
+			name:        "invalid semver constraint",
+			task:        dtos.Task{Type: "validate", Rule: "semver", Pattern: "latest"},
+			expectedErr: `invalid semver constraint "latest": improper constraint: latest`,
+		},
+		{
+			name:        "invalid inline json schema",
+			task:        dtos.Task{Type: "validate", Rule: "json_schema", Schema: map[string]interface{}{"type": "objekt"}},
+			expectedErr: `invalid schema: jsonschema inline://schema.json compilation failed: '/type' does not validate`,
+		},
+		{
+			name: "json schema file",
+			task: dtos.Task{Type: "validate", Rule: "json_schema", SchemaFile: "schemas/app.json"},
+		},
+		{
+			name:        "missing max age",
+			task:        dtos.Task{Type: "validate", Rule: "age"},
+			expectedErr: `invalid max age "": empty duration string`,
+		},
+		{
+			name: "forEach",
+			task: dtos.Task{
+				Type: "extract", Source: "jsonapi", DependsOn: []string{"slos"}, URI: "https://api.example.com:8443/slos/{{slo.id}}",
+				Headers: map[string]string{"X-Slo": "{{ slo.id }}"}, ForEach: &dtos.TaskForEach{Dependency: "slos", As: "slo", Concurrency: 4},
+			},
+		},
+		{
+			name:        "forEach on a validate fact",
+			task:        dtos.Task{Type: "validate", Rule: "unique", DependsOn: []string{"slos"}, ForEach: &dtos.TaskForEach{Dependency: "slos"}},
+			expectedErr: "forEach is only supported by extract facts",
+		},
+		{
+			name:        "forEach over an unknown dependency",
+			task:        dtos.Task{Type: "extract", Source: "jsonapi", DependsOn: []string{"slos"}, ForEach: &dtos.TaskForEach{Dependency: "alerts"}},
+			expectedErr: `forEach dependency "alerts" is not a dependency`,
+		},
+		{
+			name:        "forEach with an invalid variable",
+			task:        dtos.Task{Type: "extract", Source: "jsonapi", DependsOn: []string{"slos"}, ForEach: &dtos.TaskForEach{Dependency: "slos", As: "slo-id"}},
+			expectedErr: `invalid forEach variable "slo-id"`,
+		},
+		{
+			name: "forEach template referencing another variable",
+			task: dtos.Task{
+				Type: "extract", Source: "jsonapi", DependsOn: []string{"slos"}, QueryParams: map[string]string{"id": "{{slo}}"},
+				ForEach: &dtos.TaskForEach{Dependency: "slos"},
+			},
+			expectedErr: "template {{slo}} references an unknown variable, only item is available",
+		},
+		{
+			name: "other tasks",
+			task: dtos.Task{Type: "extract", Source: "github", Rule: "jsonpath"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.task.Validate()
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
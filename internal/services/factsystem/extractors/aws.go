@@ -0,0 +1,20 @@
+package extractors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+)
+
+// describeAWSResource returns the normalized description of the AWS resource of the task as JSON.
+func (ex *Extractor) describeAWSResource(task *dtos.Task, result string) ([]byte, error) {
+	resourceID := utils.ReplacePlaceholder(task.ResourceID, result)
+	resource, describeErr := ex.awsService.DescribeResource(task.ResourceType, resourceID)
+	if describeErr != nil {
+		return nil, fmt.Errorf("failed to describe %s %s: %v", task.ResourceType, resourceID, describeErr)
+	}
+
+	return json.Marshal(resource)
+}
@@ -0,0 +1,70 @@
+package extractors_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/awsservice"
+	awsservicemocks "github.com/motain/of-catalog/internal/services/awsservice/mocks"
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExtractor_AWS(t *testing.T) {
+	deletionProtection := true
+
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		resource    *awsservice.Resource
+		describeErr error
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name: "jsonpath on the resource",
+			task: dtos.Task{ResourceType: "rds-cluster", ResourceID: "orders-db", Rule: "jsonpath", JSONPath: ".backupRetentionPeriod >= 7 and .deletionProtection"},
+			resource: &awsservice.Resource{
+				Type:                  "rds-cluster",
+				ID:                    "orders-db",
+				BackupRetentionPeriod: 7,
+				DeletionProtection:    &deletionProtection,
+			},
+			expected: []interface{}{true},
+		},
+		{
+			name:        "describe error",
+			task:        dtos.Task{ResourceType: "rds-cluster", ResourceID: "orders-db", Rule: "notempty"},
+			describeErr: errors.New("rds cluster orders-db not found"),
+			expectedErr: "failed to describe rds-cluster orders-db: rds cluster orders-db not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAWS := awsservicemocks.NewMockAWSServiceInterface(ctrl)
+			mockAWS.EXPECT().DescribeResource(tt.task.ResourceType, tt.task.ResourceID).Return(tt.resource, tt.describeErr)
+
+			ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, mockAWS, nil, nil)
+			task := tt.task
+			task.Source = "aws"
+
+			err := ex.Extract(context.Background(), &task, nil)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
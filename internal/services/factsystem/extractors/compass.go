@@ -0,0 +1,194 @@
+package extractors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+)
+
+const compassComponentQuery = `
+	query getComponentFacts($id: ID!) {
+		compass {
+			component(id: $id) {
+				... on CompassComponent {
+					id
+					name
+					description
+					typeId
+					ownerId
+					labels {
+						name
+					}
+					links {
+						type
+						name
+						url
+					}
+					fields {
+						definition {
+							name
+						}
+						... on CompassEnumField {
+							value
+						}
+						... on CompassBooleanField {
+							booleanValue
+						}
+					}
+					relationships {
+						... on CompassRelationshipConnection {
+							nodes {
+								type
+								endNode {
+									id
+									name
+								}
+							}
+						}
+					}
+					metricSources {
+						... on CompassComponentMetricSourcesConnection {
+							nodes {
+								metricDefinition {
+									name
+								}
+								value {
+									value
+									timestamp
+								}
+							}
+						}
+					}
+				}
+				... on QueryError {
+					message
+				}
+			}
+		}
+	}`
+
+type compassComponentResponse struct {
+	Compass struct {
+		Component struct {
+			Message     string `json:"message"`
+			ID          string `json:"id"`
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			TypeID      string `json:"typeId"`
+			OwnerID     string `json:"ownerId"`
+			Labels      []struct {
+				Name string `json:"name"`
+			} `json:"labels"`
+			Links  []compassComponentLink `json:"links"`
+			Fields []struct {
+				Definition struct {
+					Name string `json:"name"`
+				} `json:"definition"`
+				Value        []string `json:"value"`
+				BooleanValue *bool    `json:"booleanValue"`
+			} `json:"fields"`
+			Relationships struct {
+				Nodes []struct {
+					Type    string                     `json:"type"`
+					EndNode compassComponentDependency `json:"endNode"`
+				} `json:"nodes"`
+			} `json:"relationships"`
+			MetricSources struct {
+				Nodes []struct {
+					MetricDefinition struct {
+						Name string `json:"name"`
+					} `json:"metricDefinition"`
+					Value *compassComponentMetricValue `json:"value"`
+				} `json:"nodes"`
+			} `json:"metricSources"`
+		} `json:"component"`
+	} `json:"compass"`
+}
+
+type compassComponentLink struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type compassComponentDependency struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type compassComponentMetricValue struct {
+	Value     *float64 `json:"value"`
+	Timestamp string   `json:"timestamp"`
+}
+
+// compassComponent is the JSON exposed to the rules of compass facts.
+type compassComponent struct {
+	ID           string                                  `json:"id"`
+	Name         string                                  `json:"name"`
+	Description  string                                  `json:"description"`
+	TypeID       string                                  `json:"typeId"`
+	OwnerID      string                                  `json:"ownerId"`
+	Labels       []string                                `json:"labels"`
+	Links        []compassComponentLink                  `json:"links"`
+	Fields       map[string]interface{}                  `json:"fields"`
+	Dependencies []compassComponentDependency            `json:"dependencies"`
+	Metrics      map[string]*compassComponentMetricValue `json:"metrics"`
+}
+
+// queryCompass fetches the Compass representation of the component of the task.
+// Enum fields are exposed as lists of values, boolean fields as booleans, metrics by metric name
+// with their latest value (null when no value was sent yet).
+// Component ids are ARIs containing colons, so placeholders are not replaced in them.
+func (ex *Extractor) queryCompass(ctx context.Context, task *dtos.Task) ([]byte, error) {
+	componentID := task.ComponentID
+	if componentID == "" {
+		return nil, fmt.Errorf("missing component id, has the component been applied?")
+	}
+
+	var response compassComponentResponse
+	if runErr := ex.compass.Run(ctx, compassComponentQuery, map[string]interface{}{"id": componentID}, &response); runErr != nil {
+		return nil, fmt.Errorf("failed to query compass component %s: %v", componentID, runErr)
+	}
+
+	raw := response.Compass.Component
+	if raw.ID == "" {
+		return nil, fmt.Errorf("compass component %s not found: %s", componentID, raw.Message)
+	}
+
+	component := compassComponent{
+		ID:           raw.ID,
+		Name:         raw.Name,
+		Description:  raw.Description,
+		TypeID:       raw.TypeID,
+		OwnerID:      raw.OwnerID,
+		Labels:       make([]string, 0, len(raw.Labels)),
+		Links:        make([]compassComponentLink, 0, len(raw.Links)),
+		Fields:       make(map[string]interface{}, len(raw.Fields)),
+		Dependencies: make([]compassComponentDependency, 0),
+		Metrics:      make(map[string]*compassComponentMetricValue, len(raw.MetricSources.Nodes)),
+	}
+
+	for _, label := range raw.Labels {
+		component.Labels = append(component.Labels, label.Name)
+	}
+	component.Links = append(component.Links, raw.Links...)
+	for _, field := range raw.Fields {
+		if field.BooleanValue != nil {
+			component.Fields[field.Definition.Name] = *field.BooleanValue
+			continue
+		}
+		component.Fields[field.Definition.Name] = field.Value
+	}
+	for _, relationship := range raw.Relationships.Nodes {
+		if relationship.Type == "DEPENDS_ON" {
+			component.Dependencies = append(component.Dependencies, relationship.EndNode)
+		}
+	}
+	for _, metricSource := range raw.MetricSources.Nodes {
+		component.Metrics[metricSource.MetricDefinition.Name] = metricSource.Value
+	}
+
+	return json.Marshal(component)
+}
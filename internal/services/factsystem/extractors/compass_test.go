@@ -0,0 +1,116 @@
+package extractors_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	compassservice "github.com/motain/of-catalog/internal/services/compassservice/mocks"
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const compassComponentResponse = `{
+	"compass": {
+		"component": {
+			"id": "ari:cloud:compass:component/1",
+			"name": "orders",
+			"typeId": "SERVICE",
+			"ownerId": "ari:cloud:teams/checkout",
+			"labels": [{"name": "tier-1"}],
+			"links": [{"type": "ON_CALL", "name": "On call", "url": "https://oncall.example.com/orders"}],
+			"fields": [
+				{"definition": {"name": "tier"}, "value": ["1"]},
+				{"definition": {"name": "isMonorepoProject"}, "booleanValue": false}
+			],
+			"relationships": {
+				"nodes": [
+					{"type": "DEPENDS_ON", "endNode": {"id": "ari:cloud:compass:component/2", "name": "payments"}},
+					{"type": "CHILD_OF", "endNode": {"id": "ari:cloud:compass:component/3", "name": "checkout"}}
+				]
+			},
+			"metricSources": {
+				"nodes": [
+					{"metricDefinition": {"name": "test-coverage"}, "value": {"value": 87.5, "timestamp": "2025-01-01T00:00:00Z"}},
+					{"metricDefinition": {"name": "open-incidents"}, "value": null}
+				]
+			}
+		}
+	}
+}`
+
+func TestExtractor_Compass(t *testing.T) {
+	tests := []struct {
+		name        string
+		response    string
+		jsonPath    string
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "on-call link",
+			response: compassComponentResponse,
+			jsonPath: `[.links[] | select(.type == "ON_CALL")] | length > 0`,
+			expected: []interface{}{true},
+		},
+		{
+			name:     "enum field",
+			response: compassComponentResponse,
+			jsonPath: `.fields.tier[0]`,
+			expected: []interface{}{"1"},
+		},
+		{
+			name:     "only DEPENDS_ON relationships are dependencies",
+			response: compassComponentResponse,
+			jsonPath: `[.dependencies[].name]`,
+			expected: []interface{}{[]interface{}{"payments"}},
+		},
+		{
+			name:     "metric value",
+			response: compassComponentResponse,
+			jsonPath: `.metrics["test-coverage"].value`,
+			expected: []interface{}{87.5},
+		},
+		{
+			name:        "component not found",
+			response:    `{"compass": {"component": {"message": "Component not found"}}}`,
+			jsonPath:    `.id`,
+			expectedErr: "compass component ari:cloud:compass:component/1 not found: Component not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCompass := compassservice.NewMockCompassServiceInterface(ctrl)
+			mockCompass.EXPECT().
+				Run(gomock.Any(), gomock.Any(), map[string]interface{}{"id": "ari:cloud:compass:component/1"}, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _ map[string]interface{}, response interface{}) error {
+					return json.Unmarshal([]byte(tt.response), response)
+				})
+
+			ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, nil, mockCompass, nil)
+			task := &dtos.Task{
+				Source:      "compass",
+				ComponentID: "ari:cloud:compass:component/1",
+				Rule:        "jsonpath",
+				JSONPath:    tt.jsonPath,
+			}
+
+			err := ex.Extract(context.Background(), task, nil)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package extractors
+
+import (
+	"context"
+	"errors"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+)
+
+// componentDefinition returns the JSON definition of the component the facts are computed for.
+func componentDefinition(ctx context.Context) ([]byte, error) {
+	component, ok := dtos.ComponentFromContext(ctx)
+	if !ok {
+		return nil, errors.New("component definition not available, component facts can only be computed for a component")
+	}
+
+	return component, nil
+}
@@ -0,0 +1,72 @@
+package extractors_test
+
+import (
+	"context"
+	"testing"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExtractor_Component(t *testing.T) {
+	component := []byte(`{
+		"metadata": {"name": "orders", "componentType": "service"},
+		"spec": {
+			"name": "orders",
+			"fields": {"tier": 1},
+			"links": [{"name": "Runbook", "type": "DOCUMENT", "url": "https://docs.example.com/orders"}],
+			"documents": [],
+			"tribe": "checkout"
+		}
+	}`)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		jsonPath    string
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "tier-1 services have an ON_CALL link",
+			ctx:      dtos.WithComponent(context.Background(), component),
+			jsonPath: `.spec.fields.tier != 1 or ([.spec.links[] | select(.type == "ON_CALL")] | length > 0)`,
+			expected: []interface{}{false},
+		},
+		{
+			name:     "metadata",
+			ctx:      dtos.WithComponent(context.Background(), component),
+			jsonPath: `.metadata.componentType`,
+			expected: []interface{}{"service"},
+		},
+		{
+			name:        "component not available",
+			ctx:         context.Background(),
+			jsonPath:    `.spec`,
+			expectedErr: "component definition not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, nil, nil, nil)
+			task := &dtos.Task{Source: "component", Rule: "jsonpath", JSONPath: tt.jsonPath}
+
+			err := ex.Extract(tt.ctx, task, nil)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package extractors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/prometheus/common/model"
+)
+
+// execProtocolVersion is sent to plugins and increased on breaking changes of the protocol.
+const execProtocolVersion = 1
+
+const defaultExecTimeout = 30 * time.Second
+
+// execRequest is written to the stdin of plugins.
+type execRequest struct {
+	Version          int                    `json:"version"`
+	Task             *dtos.Task             `json:"task"`
+	Component        json.RawMessage        `json:"component"`
+	DependencyResult string                 `json:"dependencyResult,omitempty"`
+	Dependencies     map[string]value.Value `json:"dependencies"`
+}
+
+// execResponse is read from the stdout of plugins, a non empty error fails the fact.
+type execResponse struct {
+	Result value.Value `json:"result"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// execSource runs the plugin of exec facts. Without a rule the result of the plugin is the result
+// of the fact, otherwise the rule is applied to the JSON result.
+type execSource struct{}
+
+func (s execSource) Extract(ctx context.Context, task *dtos.Task, dependencyResult string) ([]byte, error) {
+	result, runErr := runPlugin(ctx, task, dependencyResult)
+	if runErr != nil {
+		return nil, runErr
+	}
+
+	return json.Marshal(result)
+}
+
+func (s execSource) ApplyRule(ctx context.Context, task *dtos.Task, dependencyResult string) (value.Value, bool, error) {
+	if task.Rule != "" {
+		return value.Null, false, nil
+	}
+
+	result, runErr := runPlugin(ctx, task, dependencyResult)
+	if runErr != nil {
+		return value.Null, true, fmt.Errorf("failed to process request for source %s: %v", task.Source, runErr)
+	}
+
+	return result, true, nil
+}
+
+func runPlugin(ctx context.Context, task *dtos.Task, dependencyResult string) (value.Value, error) {
+	if task.Exec == nil || task.Exec.Command == "" {
+		return value.Null, errors.New("exec.command is required")
+	}
+
+	timeout := defaultExecTimeout
+	if task.Exec.Timeout != "" {
+		parsed, parseErr := model.ParseDuration(task.Exec.Timeout)
+		if parseErr != nil {
+			return value.Null, fmt.Errorf("invalid exec timeout %q: %v", task.Exec.Timeout, parseErr)
+		}
+		timeout = time.Duration(parsed)
+	}
+
+	payload, marshalErr := json.Marshal(newExecRequest(ctx, task, dependencyResult))
+	if marshalErr != nil {
+		return value.Null, fmt.Errorf("failed to encode plugin request: %v", marshalErr)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, task.Exec.Command, task.Exec.Args...)
+	cmd.Env = pluginEnv(task.Exec.Env)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait for children of the plugin still holding its output once it is killed
+	cmd.WaitDelay = time.Second
+
+	runErr := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return value.Null, fmt.Errorf("plugin %s timed out after %s", task.Exec.Command, timeout)
+	}
+	if runErr != nil {
+		return value.Null, fmt.Errorf("plugin %s failed: %v: %s", task.Exec.Command, runErr, strings.TrimSpace(stderr.String()))
+	}
+
+	var response execResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return value.Null, fmt.Errorf("plugin %s returned an invalid response: %v", task.Exec.Command, err)
+	}
+	if response.Error != "" {
+		return value.Null, fmt.Errorf("plugin %s failed: %s", task.Exec.Command, response.Error)
+	}
+
+	return response.Result, nil
+}
+
+func newExecRequest(ctx context.Context, task *dtos.Task, dependencyResult string) execRequest {
+	request := execRequest{
+		Version:          execProtocolVersion,
+		Task:             task,
+		DependencyResult: dependencyResult,
+		Dependencies:     make(map[string]value.Value, len(task.Dependencies)),
+	}
+
+	if component, ok := dtos.ComponentFromContext(ctx); ok {
+		request.Component = component
+	}
+
+	for _, dep := range task.Dependencies {
+		request.Dependencies[dep.ID] = dep.Result
+	}
+
+	return request
+}
+
+// pluginEnv returns the environment of plugins: PATH and the allowed variables defined in the environment of ofc.
+func pluginEnv(allowed []string) []string {
+	env := []string{"PATH=" + os.Getenv("PATH")}
+	for _, name := range allowed {
+		if envValue, found := os.LookupEnv(name); found {
+			env = append(env, name+"="+envValue)
+		}
+	}
+	return env
+}
@@ -0,0 +1,142 @@
+package extractors_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// TestExecPlugin is the plugin run by TestExtractor_Exec, it only runs when started as a plugin.
+func TestExecPlugin(t *testing.T) {
+	mode := os.Getenv("OFC_TEST_PLUGIN")
+	if mode == "" {
+		return
+	}
+
+	var request map[string]interface{}
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid request: %v", err)
+		os.Exit(1)
+	}
+
+	switch mode {
+	case "echo":
+		request["secret"] = os.Getenv("OFC_TEST_SECRET")
+		_ = json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"result": request})
+	case "count":
+		fmt.Print(`{"result": {"findings": 2}}`)
+	case "error":
+		fmt.Print(`{"error": "Dockerfile not found"}`)
+	case "crash":
+		fmt.Fprint(os.Stderr, "boom")
+		os.Exit(3)
+	case "sleep":
+		time.Sleep(5 * time.Second)
+	case "invalid":
+		fmt.Print("OK")
+	}
+	os.Exit(0)
+}
+
+func TestExtractor_Exec(t *testing.T) {
+	t.Setenv("OFC_TEST_SECRET", "s3cr3t")
+
+	plugin := func() *dtos.TaskExec {
+		return &dtos.TaskExec{
+			Command: os.Args[0],
+			Args:    []string{"-test.run=^TestExecPlugin$"},
+			Env:     []string{"OFC_TEST_PLUGIN"},
+			Timeout: "10s",
+		}
+	}
+
+	tests := []struct {
+		name        string
+		mode        string
+		task        dtos.Task
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name: "request",
+			mode: "echo",
+			task: dtos.Task{ID: "dockerfile-check", Rule: "jsonpath", JSONPath: `[.version, .task.id, .component.metadata.name, .dependencyResult, .dependencies.repo, .secret]`},
+			expected: []interface{}{
+				[]interface{}{1.0, "dockerfile-check", "my-service", "motain/my-service", "motain/my-service", ""},
+			},
+		},
+		{
+			name:     "result without rule",
+			mode:     "count",
+			expected: map[string]interface{}{"findings": 2.0},
+		},
+		{
+			name:     "rule applied to the result",
+			mode:     "count",
+			task:     dtos.Task{Rule: "jsonpath", JSONPath: ".findings"},
+			expected: []interface{}{2.0},
+		},
+		{
+			name:        "error reported by the plugin",
+			mode:        "error",
+			expectedErr: "failed: Dockerfile not found",
+		},
+		{
+			name:        "plugin exiting with an error",
+			mode:        "crash",
+			expectedErr: "exit status 3: boom",
+		},
+		{
+			name:        "plugin timing out",
+			mode:        "sleep",
+			task:        dtos.Task{Exec: &dtos.TaskExec{Timeout: "200ms"}},
+			expectedErr: "timed out after 200ms",
+		},
+		{
+			name:        "invalid response",
+			mode:        "invalid",
+			expectedErr: "returned an invalid response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			t.Setenv("OFC_TEST_PLUGIN", tt.mode)
+
+			task := tt.task
+			task.Source = "exec"
+			exec := plugin()
+			if task.Exec != nil {
+				exec.Timeout = task.Exec.Timeout
+			}
+			task.Exec = exec
+
+			dep := &dtos.Task{ID: "repo", Result: value.String("motain/my-service")}
+			task.Dependencies = []*dtos.Task{dep}
+			ctx := dtos.WithComponent(context.Background(), []byte(`{"metadata": {"name": "my-service"}}`))
+
+			ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, nil, nil, nil)
+			err := ex.Extract(ctx, &task, task.Dependencies)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
@@ -4,18 +4,20 @@ package extractors
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"path/filepath"
 	"regexp"
 	"strconv"
 
+	"github.com/motain/of-catalog/internal/services/awsservice"
+	"github.com/motain/of-catalog/internal/services/codesearchservice"
+	"github.com/motain/of-catalog/internal/services/compassservice"
 	"github.com/motain/of-catalog/internal/services/configservice"
 	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/registry"
 	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
 	"github.com/motain/of-catalog/internal/services/githubservice"
 	"github.com/motain/of-catalog/internal/services/jsonservice"
 	"github.com/motain/of-catalog/internal/services/prometheusservice"
@@ -31,6 +33,10 @@ type Extractor struct {
 	jsonService       jsonservice.JSONServiceInterface
 	github            githubservice.GitHubServiceInterface
 	prometheusService prometheusservice.PrometheusServiceInterface
+	codeSearch        codesearchservice.CodeSearchServiceInterface
+	awsService        awsservice.AWSServiceInterface
+	compass           compassservice.CompassServiceInterface
+	registry          *registry.Registry
 }
 
 func NewExtractor(
@@ -38,11 +44,35 @@ func NewExtractor(
 	jsonService jsonservice.JSONServiceInterface,
 	github githubservice.GitHubServiceInterface,
 	prometheusService prometheusservice.PrometheusServiceInterface,
+	codeSearch codesearchservice.CodeSearchServiceInterface,
+	awsService awsservice.AWSServiceInterface,
+	compass compassservice.CompassServiceInterface,
+	reg *registry.Registry,
 ) *Extractor {
-	return &Extractor{config: config, jsonService: jsonService, github: github, prometheusService: prometheusService}
+	if reg == nil {
+		reg = registry.NewRegistry()
+	}
+
+	ex := &Extractor{
+		config:            config,
+		jsonService:       jsonService,
+		github:            github,
+		prometheusService: prometheusService,
+		codeSearch:        codeSearch,
+		awsService:        awsService,
+		compass:           compass,
+		registry:          reg,
+	}
+	ex.registerBuiltins()
+
+	return ex
 }
 
 func (ex *Extractor) Extract(ctx context.Context, task *dtos.Task, deps []*dtos.Task) error {
+	if task.ForEach != nil {
+		return ex.handleForEach(ctx, task, deps)
+	}
+
 	if len(deps) > 1 {
 		return errors.New("too many dependencies provided in extract context")
 	}
@@ -51,31 +81,32 @@ func (ex *Extractor) Extract(ctx context.Context, task *dtos.Task, deps []*dtos.
 		return ex.handleSingleResult(ctx, task, "")
 	}
 
-	if deps[0].Result == nil {
+	if deps[0].Result.IsNull() {
 		return errors.New("dependency result not provided")
 	}
 
-	if values, ok := deps[0].Result.([]interface{}); ok {
+	if values, listErr := deps[0].Result.List(); listErr == nil {
 		if len(values) == 0 {
 			return errors.New("dependency result not provided")
 		}
 
 		stringValues := make([]string, len(values))
-		for i, value := range values {
-			stringValues[i] = fmt.Sprintf("%v", value)
+		for i, item := range values {
+			stringValue, convErr := item.ToString()
+			if convErr != nil {
+				return fmt.Errorf("invalid dependency result: %v", convErr)
+			}
+			stringValues[i] = stringValue
 		}
 		return ex.handleMultipleResults(ctx, task, stringValues)
 	}
 
-	if values, ok := deps[0].Result.([]string); ok {
-		if len(values) == 0 {
-			return errors.New("dependency result not provided")
-		}
-
-		return ex.handleMultipleResults(ctx, task, values)
+	stringValue, convErr := deps[0].Result.ToString()
+	if convErr != nil {
+		return fmt.Errorf("invalid dependency result: %v", convErr)
 	}
 
-	return ex.handleSingleResult(ctx, task, fmt.Sprintf("%v", deps[0].Result))
+	return ex.handleSingleResult(ctx, task, stringValue)
 }
 
 func (ex *Extractor) handleSingleResult(ctx context.Context, task *dtos.Task, dependencyResult string) error {
@@ -87,68 +118,126 @@ func (ex *Extractor) handleSingleResult(ctx context.Context, task *dtos.Task, de
 	return nil
 }
 
+// handleMultipleResults runs the task for every item of the result of its dependency, the results
+// are flattened into a single list. Items without result (e.g. missing files) are skipped.
 func (ex *Extractor) handleMultipleResults(ctx context.Context, task *dtos.Task, dependencyResults []string) error {
-	results := make([]string, 0)
-	for _, value := range dependencyResults {
-		result, processErr := ex.processData(ctx, task, value)
+	results := make([]value.Value, 0)
+	for _, dependencyResult := range dependencyResults {
+		result, processErr := ex.processData(ctx, task, dependencyResult)
 		if processErr != nil {
 			return fmt.Errorf("multiple results handler failed to process request: %v", processErr)
 		}
 
-		if values, ok := result.([]interface{}); ok {
-			for _, v := range values {
-				results = append(results, fmt.Sprintf("%v", v))
-			}
-			continue
-		}
-
-		if values, ok := result.([]string); ok {
-			results = append(results, values...)
-			continue
-		}
-
-		if value, ok := result.(string); ok {
-			results = append(results, value)
-			continue
-		}
+		results = append(results, result.Items()...)
 	}
 
-	task.Result = results
+	task.Result = value.List(results...)
 	return nil
 }
 
-func (ex *Extractor) processData(ctx context.Context, task *dtos.Task, dependencyResult string) (interface{}, error) {
-	var jsonData []byte
-	var dataErr error
-	switch dtos.TaskSource(task.Source) {
-	case dtos.GitHubTaskSource:
-		if task.Rule == string(dtos.SearchRule) {
-			searchListResult, searchErr := ex.github.Search(task.Repo, task.SearchString)
-			if searchErr != nil {
-				return nil, fmt.Errorf("failed to process github Search request for source for string %s %s: %v", task.SearchString, task.Source, searchErr)
-			}
-			return len(searchListResult) != 0, nil
+func (ex *Extractor) processData(ctx context.Context, task *dtos.Task, dependencyResult string) (value.Value, error) {
+	source, found := ex.registry.Source(task.Source)
+	if !found {
+		return value.Null, fmt.Errorf("no data extracted, unknown source %s", task.Source)
+	}
+
+	if ruleSource, isRuleSource := source.(registry.RuleSource); isRuleSource {
+		result, handled, ruleErr := ruleSource.ApplyRule(ctx, task, unquoted(dependencyResult))
+		if handled {
+			return result, ruleErr
 		}
-		jsonData, dataErr = ex.processGithub(task, unquoted(dependencyResult))
-	case dtos.JSONAPITaskSource:
-		jsonData, dataErr = ex.processJSONAPI(ctx, task, unquoted(dependencyResult))
-	case dtos.PrometheusTaskSource:
-		jsonData, dataErr = ex.queryPrometheus(task, unquoted(dependencyResult))
-	default:
-		return nil, fmt.Errorf("no data extracted, unknown source %s", task.Source)
 	}
+
+	jsonData, dataErr := source.Extract(ctx, task, unquoted(dependencyResult))
 	if dataErr != nil {
-		return nil, fmt.Errorf("failed to process request for source %s: %v", task.Source, dataErr)
+		return value.Null, fmt.Errorf("failed to process request for source %s: %v", task.Source, dataErr)
 	}
 
-	switch dtos.TaskRule(task.Rule) {
-	case dtos.JSONPathRule:
-		return utils.InspectExtractedData(task.JSONPath, jsonData)
-	case dtos.NotEmptyRule:
-		return jsonData != nil, nil
-	default:
-		return jsonData, nil
+	// Without a known rule the raw data is returned
+	rule, found := ex.registry.ExtractRule(task.Rule)
+	if !found {
+		return value.Of(jsonData)
 	}
+
+	return rule.Apply(ctx, task, jsonData)
+}
+
+// registerBuiltins registers the sources and rules shipped with the fact system.
+func (ex *Extractor) registerBuiltins() {
+	ex.registry.RegisterSource(string(dtos.GitHubTaskSource), searchableSource{
+		SourceFunc: func(_ context.Context, task *dtos.Task, result string) ([]byte, error) {
+			return ex.processGithub(task, result)
+		},
+		search: ex.processGithubSearch,
+	})
+	ex.registry.RegisterSource(string(dtos.LocalTaskSource), searchableSource{
+		SourceFunc: func(_ context.Context, task *dtos.Task, result string) ([]byte, error) {
+			return ex.processLocal(task, result)
+		},
+		search: ex.processLocalSearch,
+	})
+	ex.registry.RegisterSource(string(dtos.JSONAPITaskSource), registry.SourceFunc(ex.processJSONAPI))
+	ex.registry.RegisterSource(string(dtos.PrometheusTaskSource), registry.SourceFunc(
+		func(_ context.Context, task *dtos.Task, result string) ([]byte, error) {
+			return ex.queryPrometheus(task, result)
+		},
+	))
+	ex.registry.RegisterSource(string(dtos.AWSTaskSource), registry.SourceFunc(
+		func(_ context.Context, task *dtos.Task, result string) ([]byte, error) {
+			return ex.describeAWSResource(task, result)
+		},
+	))
+	ex.registry.RegisterSource(string(dtos.CompassTaskSource), registry.SourceFunc(
+		func(ctx context.Context, task *dtos.Task, _ string) ([]byte, error) {
+			return ex.queryCompass(ctx, task)
+		},
+	))
+	ex.registry.RegisterSource(string(dtos.ComponentTaskSource), registry.SourceFunc(
+		func(ctx context.Context, _ *dtos.Task, _ string) ([]byte, error) {
+			return componentDefinition(ctx)
+		},
+	))
+	ex.registry.RegisterSource(string(dtos.ExecTaskSource), execSource{})
+
+	ex.registry.RegisterExtractRule(string(dtos.JSONPathRule), registry.ExtractRuleFunc(
+		func(_ context.Context, task *dtos.Task, data []byte) (value.Value, error) {
+			return typed(utils.InspectExtractedData(task.JSONPath, data))
+		},
+	))
+	ex.registry.RegisterExtractRule(string(dtos.NotEmptyRule), registry.ExtractRuleFunc(
+		func(_ context.Context, _ *dtos.Task, data []byte) (value.Value, error) {
+			return value.Bool(data != nil), nil
+		},
+	))
+	ex.registry.RegisterExtractRule(string(dtos.VulnerabilitiesRule), registry.ExtractRuleFunc(
+		func(_ context.Context, task *dtos.Task, data []byte) (value.Value, error) {
+			return typed(countVulnerabilities(task, data))
+		},
+	))
+}
+
+// searchableSource is a source of files also supporting the search rule.
+type searchableSource struct {
+	registry.SourceFunc
+	search func(task *dtos.Task) (interface{}, error)
+}
+
+func (s searchableSource) ApplyRule(_ context.Context, task *dtos.Task, _ string) (value.Value, bool, error) {
+	if dtos.TaskRule(task.Rule) != dtos.SearchRule {
+		return value.Null, false, nil
+	}
+
+	result, err := typed(s.search(task))
+	return result, true, err
+}
+
+// typed converts the result of a source or a rule to a fact result.
+func typed(result interface{}, err error) (value.Value, error) {
+	if err != nil {
+		return value.Null, err
+	}
+
+	return value.Of(result)
 }
 
 func (ex *Extractor) processGithub(task *dtos.Task, result string) ([]byte, error) {
@@ -162,55 +251,30 @@ func (ex *Extractor) processGithub(task *dtos.Task, result string) ([]byte, erro
 		return nil, fileErr
 	}
 
+	return fileToJSON(task, extractFilePath, []byte(fileContent))
+}
+
+// fileToJSON prepares the content of a file for the task rule, converting toml files to json
+// when the jsonpath rule is used.
+func fileToJSON(task *dtos.Task, filePath string, fileContent []byte) ([]byte, error) {
 	if dtos.TaskRule(task.Rule) != dtos.JSONPathRule {
-		return []byte(fileContent), nil
+		return fileContent, nil
 	}
 
-	fileExtension := filepath.Ext(task.FilePath)
+	fileExtension := filepath.Ext(filePath)
 	if fileExtension != ".json" && fileExtension != ".toml" {
 		return nil, fmt.Errorf("unsupported file extension: %s", fileExtension)
 	}
 
 	if fileExtension == ".toml" {
-		jsonData, transformErr := transformers.Toml2json(fileContent)
+		jsonData, transformErr := transformers.Toml2json(string(fileContent))
 		if transformErr != nil {
 			return nil, fmt.Errorf("failed to transform toml file to json: %v", transformErr)
 		}
 		return jsonData, nil
 	}
 
-	return []byte(fileContent), nil
-}
-
-func (ex *Extractor) processJSONAPI(ctx context.Context, task *dtos.Task, result string) ([]byte, error) {
-	extractURI := utils.ReplacePlaceholder(task.URI, result)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, extractURI, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
-	}
-
-	if task.Auth != nil {
-		token := ex.config.Get(task.Auth.TokenVar)
-		req.Header.Set(task.Auth.Header, token)
-	}
-
-	resp, fileErr := ex.jsonService.Do(req)
-	if fileErr != nil {
-		return nil, fileErr
-	}
-
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			fmt.Printf("failed to close response body: %v", err)
-		}
-	}(resp.Body)
-	jsonData, readErr := io.ReadAll(resp.Body)
-	if readErr != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", readErr)
-	}
-
-	return jsonData, nil
+	return fileContent, nil
 }
 
 func unquoted(toUnquote string) string {
@@ -221,13 +285,3 @@ func unquoted(toUnquote string) string {
 
 	return unquoted
 }
-
-func (ex *Extractor) queryPrometheus(task *dtos.Task, result string) ([]byte, error) {
-	prometheusQuery := utils.ReplacePlaceholder(task.PrometheusQuery, result)
-	response, err := ex.prometheusService.InstantQuery(prometheusQuery)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query prometheus: %v", err)
-	}
-
-	return json.Marshal(response)
-}
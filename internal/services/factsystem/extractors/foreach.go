@@ -0,0 +1,104 @@
+package extractors
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+)
+
+// handleForEach runs the task once per item of the result of its forEach dependency, rendering the
+// {{variable}} templates of the task with the item. At most forEach.concurrency items run at the
+// same time. The result is an object with the result of every item keyed by the item, items without
+// result (e.g. missing files) are skipped. The results of forEach dependencies are iterated in key
+// order, so that forEach facts can be chained.
+func (ex *Extractor) handleForEach(ctx context.Context, task *dtos.Task, deps []*dtos.Task) error {
+	var dependency *dtos.Task
+	for _, dep := range deps {
+		if dep.ID == task.ForEach.Dependency {
+			dependency = dep
+		}
+	}
+	if dependency == nil || dependency.Result.IsNull() {
+		return fmt.Errorf("forEach dependency %s result not provided", task.ForEach.Dependency)
+	}
+
+	items := dependency.Result.Items()
+	if dependency.ForEach != nil {
+		items = forEachItems(dependency.Result)
+	}
+	results := make([]value.Value, len(items))
+	errs := make([]error, len(items))
+
+	concurrency := task.ForEach.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, item value.Value) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i], errs[i] = ex.processItem(ctx, task, item)
+		}(i, item)
+	}
+	wg.Wait()
+
+	fields := make(map[string]value.Value, len(items))
+	for i, item := range items {
+		if errs[i] != nil {
+			return fmt.Errorf("forEach failed to process item %s: %v", item, errs[i])
+		}
+		if !results[i].IsNull() {
+			fields[itemKey(item)] = results[i]
+		}
+	}
+
+	task.Result = value.Object(fields)
+	return nil
+}
+
+func (ex *Extractor) processItem(ctx context.Context, task *dtos.Task, item value.Value) (value.Value, error) {
+	variables := map[string]value.Value{task.ForEach.Variable(): item}
+	itemTask, renderErr := task.WithTemplates(func(field string) (string, error) {
+		return utils.ReplaceVariables(field, variables)
+	})
+	if renderErr != nil {
+		return value.Null, renderErr
+	}
+
+	return ex.processData(ctx, itemTask, itemKey(item))
+}
+
+// forEachItems flattens the results of a forEach fact in the order of their keys.
+func forEachItems(result value.Value) []value.Value {
+	fields, _ := result.Object()
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	items := make([]value.Value, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, fields[key].Items()...)
+	}
+	return items
+}
+
+// itemKey returns the key of the result of an item: scalars are keyed by their text, lists and
+// objects by their JSON encoding.
+func itemKey(item value.Value) string {
+	if text, err := item.ToString(); err == nil {
+		return text
+	}
+	return item.Key()
+}
@@ -0,0 +1,105 @@
+package extractors_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExtractor_ForEach(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "services"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "services", "api.json"), []byte(`{"replicas": 3}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "services", "worker.json"), []byte(`{"replicas": 1}`), 0644))
+
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		dependency  interface{}
+		chained     bool
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:       "list of names",
+			task:       dtos.Task{FilePath: "services/{{item}}.json", ForEach: &dtos.TaskForEach{Dependency: "services", Concurrency: 2}},
+			dependency: []string{"api", "worker"},
+			expected:   map[string]interface{}{"api": []float64{3}, "worker": []float64{1}},
+		},
+		{
+			name: "named variable with path",
+			task: dtos.Task{
+				FilePath: "services/{{ service.name }}.json",
+				ForEach:  &dtos.TaskForEach{Dependency: "services", As: "service"},
+			},
+			dependency: []interface{}{map[string]string{"name": "api"}},
+			expected:   map[string]interface{}{`{"name":"api"}`: []float64{3}},
+		},
+		{
+			name:       "single item",
+			task:       dtos.Task{FilePath: "services/{{item}}.json", ForEach: &dtos.TaskForEach{Dependency: "services"}},
+			dependency: "worker",
+			expected:   map[string]interface{}{"worker": []float64{1}},
+		},
+		{
+			name:       "results of another forEach fact",
+			task:       dtos.Task{FilePath: "services/{{item}}.json", ForEach: &dtos.TaskForEach{Dependency: "services"}},
+			dependency: map[string]interface{}{"b": []string{"worker"}, "a": []string{"api"}},
+			chained:    true,
+			expected:   map[string]interface{}{"api": []float64{3}, "worker": []float64{1}},
+		},
+		{
+			name:        "missing dependency result",
+			task:        dtos.Task{FilePath: "services/{{item}}.json", ForEach: &dtos.TaskForEach{Dependency: "services"}},
+			expectedErr: "forEach dependency services result not provided",
+		},
+		{
+			name:        "unknown variable",
+			task:        dtos.Task{FilePath: "services/{{name}}.json", ForEach: &dtos.TaskForEach{Dependency: "services"}},
+			dependency:  []string{"api"},
+			expectedErr: "forEach failed to process item api: unknown variable name",
+		},
+		{
+			name:        "unknown field",
+			task:        dtos.Task{FilePath: "services/{{item.id}}.json", ForEach: &dtos.TaskForEach{Dependency: "services"}},
+			dependency:  []interface{}{map[string]string{"name": "api"}},
+			expectedErr: "variable item.id: unknown field id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, nil, nil, nil)
+			task := tt.task
+			task.Source = "local"
+			task.Path = root
+			task.Rule = "jsonpath"
+			task.JSONPath = ".replicas"
+			deps := []*dtos.Task{{ID: "services", Result: value.MustOf(tt.dependency)}}
+			if tt.chained {
+				deps[0].ForEach = &dtos.TaskForEach{Dependency: "teams"}
+			}
+
+			err := ex.Extract(context.Background(), &task, deps)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+			assert.Equal(t, "services/{{", task.FilePath[:11], "the templates of the fact are kept")
+		})
+	}
+}
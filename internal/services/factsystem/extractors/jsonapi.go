@@ -0,0 +1,374 @@
+package extractors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+)
+
+const (
+	// defaultMaxPages bounds paginated requests not declaring maxPages
+	defaultMaxPages = 10
+	// defaultPageParam is the query parameter used by page pagination not declaring pageParam
+	defaultPageParam = "page"
+	// defaultStartPage is the first page of page pagination not declaring startPage
+	defaultStartPage = 1
+)
+
+// processJSONAPI calls the JSON API described by the task.
+// Paginated requests return a JSON array with the items of every page.
+// Placeholders of the URI, the query parameters and the body are replaced with the dependency result.
+func (ex *Extractor) processJSONAPI(ctx context.Context, task *dtos.Task, result string) ([]byte, error) {
+	extractURI := utils.ReplacePlaceholder(task.URI, result)
+	params := queryParams(task, result)
+	if result != "" && task.Body != "" {
+		requestTask := *task
+		requestTask.Body = utils.ReplacePlaceholder(task.Body, result)
+		task = &requestTask
+	}
+
+	if task.Pagination == nil {
+		jsonData, _, requestErr := ex.requestJSONAPI(ctx, task, extractURI, params)
+		return jsonData, requestErr
+	}
+
+	return ex.paginateJSONAPI(ctx, task, extractURI, params)
+}
+
+func (ex *Extractor) paginateJSONAPI(ctx context.Context, task *dtos.Task, requestURI string, params url.Values) ([]byte, error) {
+	pagination := task.Pagination
+
+	maxPages := pagination.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxPages
+	}
+
+	pageParam := pagination.PageParam
+	if pageParam == "" {
+		pageParam = defaultPageParam
+	}
+	page := defaultStartPage
+	if pagination.StartPage != nil {
+		page = *pagination.StartPage
+	}
+
+	paginationType := dtos.TaskPaginationType(pagination.Type)
+	switch paginationType {
+	case dtos.LinkPagination:
+	case dtos.CursorPagination:
+		if pagination.CursorPath == "" || pagination.CursorParam == "" {
+			return nil, fmt.Errorf("cursor pagination requires cursorPath and cursorParam")
+		}
+	case dtos.PagePagination:
+		params.Set(pageParam, strconv.Itoa(page))
+	default:
+		return nil, fmt.Errorf("unknown pagination type %s", pagination.Type)
+	}
+
+	items := make([]interface{}, 0)
+	hasNext := true
+	for i := 0; hasNext; i++ {
+		// a partial listing would silently skew the metric
+		if i == maxPages {
+			return nil, fmt.Errorf("more than %d pages, raise maxPages", maxPages)
+		}
+
+		jsonData, headers, requestErr := ex.requestJSONAPI(ctx, task, requestURI, params)
+		if requestErr != nil {
+			return nil, requestErr
+		}
+
+		pageItems, itemsErr := extractPageItems(pagination.ItemsPath, jsonData)
+		if itemsErr != nil {
+			return nil, fmt.Errorf("failed to extract items from page %d: %v", i+1, itemsErr)
+		}
+		items = append(items, pageItems...)
+
+		hasNext = false
+		switch paginationType {
+		case dtos.LinkPagination:
+			var nextURI string
+			nextURI, hasNext = nextLink(requestURI, headers.Get("Link"))
+			// The next link already carries every query parameter
+			requestURI, params = nextURI, url.Values{}
+		case dtos.CursorPagination:
+			var cursor string
+			cursor, hasNext = nextCursor(pagination.CursorPath, jsonData)
+			params.Set(pagination.CursorParam, cursor)
+		case dtos.PagePagination:
+			hasNext = len(pageItems) > 0
+			page++
+			params.Set(pageParam, strconv.Itoa(page))
+		}
+	}
+
+	return json.Marshal(items)
+}
+
+// requestJSONAPI sends a request and returns the response body and headers.
+// Rate limited (429) and failed (5xx) requests are retried according to the task retry policy (by default
+// only GET and HEAD requests),
+// responses with an unexpected status code or a non JSON content type are reported as errors.
+func (ex *Extractor) requestJSONAPI(ctx context.Context, task *dtos.Task, requestURI string, params url.Values) ([]byte, http.Header, error) {
+	parsedURI, parseErr := url.Parse(requestURI)
+	if parseErr != nil {
+		return nil, nil, fmt.Errorf("invalid uri %s: %v", requestURI, parseErr)
+	}
+
+	query := parsedURI.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	parsedURI.RawQuery = query.Encode()
+
+	method := http.MethodGet
+	if task.HTTPMethod != "" {
+		method = strings.ToUpper(task.HTTPMethod)
+	}
+
+	policy, policyErr := newRetryPolicy(task.Retry, method)
+	if policyErr != nil {
+		return nil, nil, policyErr
+	}
+
+	// the request is built once: configuration errors are not transient and must not be retried
+	req, reqErr := ex.newJSONAPIRequest(ctx, task, method, parsedURI.String())
+	if reqErr != nil {
+		return nil, nil, reqErr
+	}
+
+	var resp *http.Response
+	var jsonData []byte
+	var sendErr error
+	for attempt := 1; ; attempt++ {
+		resp, jsonData, sendErr = ex.sendJSONAPIRequest(req)
+		if attempt >= policy.attempts || !shouldRetry(resp, sendErr) {
+			break
+		}
+
+		if waitErr := wait(ctx, policy.delay(attempt, resp)); waitErr != nil {
+			return nil, nil, waitErr
+		}
+	}
+	if sendErr != nil {
+		return nil, nil, sendErr
+	}
+
+	if !isExpectedStatus(task.ExpectedStatus, resp.StatusCode) {
+		return nil, nil, fmt.Errorf("unexpected status code %d from %s %s: %s", resp.StatusCode, method, parsedURI.Redacted(), summarize(jsonData))
+	}
+
+	if len(jsonData) > 0 && !isJSONContentType(resp.Header.Get("Content-Type")) {
+		return nil, nil, fmt.Errorf("unexpected content type %s from %s %s", resp.Header.Get("Content-Type"), method, parsedURI.Redacted())
+	}
+
+	return jsonData, resp.Header, nil
+}
+
+// newJSONAPIRequest builds the authenticated request of the task.
+func (ex *Extractor) newJSONAPIRequest(ctx context.Context, task *dtos.Task, method, requestURI string) (*http.Request, error) {
+	var body io.Reader
+	if task.Body != "" {
+		body = strings.NewReader(task.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURI, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	for name, value := range task.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if authErr := ex.authenticate(req, task.Auth); authErr != nil {
+		return nil, authErr
+	}
+
+	return req, nil
+}
+
+// sendJSONAPIRequest sends a single attempt of the request, with a fresh copy of its body.
+func (ex *Extractor) sendJSONAPIRequest(req *http.Request) (*http.Response, []byte, error) {
+	attempt := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, nil, fmt.Errorf("failed to copy request body: %v", bodyErr)
+		}
+		attempt.Body = body
+	}
+
+	resp, fileErr := ex.jsonService.Do(attempt)
+	if fileErr != nil {
+		return nil, nil, fileErr
+	}
+
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			fmt.Printf("failed to close response body: %v", err)
+		}
+	}(resp.Body)
+	jsonData, readErr := io.ReadAll(resp.Body)
+	if readErr != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %v", readErr)
+	}
+
+	return resp, jsonData, nil
+}
+
+// authenticate adds the credentials read from the environment variables named in auth to the request.
+func (ex *Extractor) authenticate(req *http.Request, auth *dtos.TaskAuth) error {
+	if auth == nil {
+		return nil
+	}
+
+	switch dtos.TaskAuthType(auth.Type) {
+	case "", dtos.HeaderAuthType:
+		req.Header.Set(auth.Header, ex.config.Get(auth.TokenVar))
+	case dtos.BasicAuthType:
+		req.SetBasicAuth(ex.config.Get(auth.UsernameVar), ex.config.Get(auth.PasswordVar))
+	case dtos.BearerAuthType:
+		header := auth.Header
+		if header == "" {
+			header = "Authorization"
+		}
+		req.Header.Set(header, "Bearer "+ex.config.Get(auth.TokenVar))
+	default:
+		return fmt.Errorf("unknown auth type %s", auth.Type)
+	}
+
+	return nil
+}
+
+// queryParams returns the query parameters of the task, replacing placeholders with the dependency result.
+func queryParams(task *dtos.Task, result string) url.Values {
+	params := url.Values{}
+	for name, value := range task.QueryParams {
+		if result != "" {
+			value = utils.ReplacePlaceholder(value, result)
+		}
+		params.Set(name, value)
+	}
+	return params
+}
+
+// extractPageItems returns the items of a page. itemsPath defaults to the whole response,
+// arrays are flattened so that pages can be concatenated.
+func extractPageItems(itemsPath string, jsonData []byte) ([]interface{}, error) {
+	if itemsPath == "" {
+		itemsPath = "."
+	}
+
+	values, inspectErr := utils.InspectExtractedData(itemsPath, jsonData)
+	if inspectErr != nil {
+		return nil, inspectErr
+	}
+
+	items := make([]interface{}, 0)
+	for _, value := range values.([]interface{}) {
+		if list, isList := value.([]interface{}); isList {
+			items = append(items, list...)
+			continue
+		}
+		if value != nil {
+			items = append(items, value)
+		}
+	}
+
+	return items, nil
+}
+
+// nextCursor returns the first non empty value found at cursorPath.
+func nextCursor(cursorPath string, jsonData []byte) (string, bool) {
+	values, inspectErr := utils.InspectExtractedData(cursorPath, jsonData)
+	if inspectErr != nil {
+		return "", false
+	}
+
+	for _, value := range values.([]interface{}) {
+		if value == nil || value == false {
+			continue
+		}
+		if cursor := fmt.Sprintf("%v", value); cursor != "" {
+			return cursor, true
+		}
+	}
+
+	return "", false
+}
+
+// nextLink returns the rel="next" URL of a Link header (RFC 8288), resolved against the current URI.
+func nextLink(currentURI, linkHeader string) (string, bool) {
+	for _, link := range strings.Split(linkHeader, ",") {
+		parts := strings.Split(link, ";")
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+
+		for _, param := range parts[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(name) != "rel" || !slices.Contains(strings.Fields(strings.Trim(value, `"`)), "next") {
+				continue
+			}
+
+			base, baseErr := url.Parse(currentURI)
+			next, nextErr := url.Parse(strings.Trim(target, "<>"))
+			if baseErr != nil || nextErr != nil {
+				return "", false
+			}
+			return base.ResolveReference(next).String(), true
+		}
+	}
+
+	return "", false
+}
+
+// isExpectedStatus reports whether the status code is accepted, any 2xx code is accepted by default.
+func isExpectedStatus(expectedStatus []int, statusCode int) bool {
+	if len(expectedStatus) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	return slices.Contains(expectedStatus, statusCode)
+}
+
+// isJSONContentType accepts a missing content type as well as application/json and its variants
+// (e.g. application/vnd.api+json, application/problem+json).
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, parseErr := mime.ParseMediaType(contentType)
+	if parseErr != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// summarize shortens a response body so that it can be included in error messages.
+func summarize(body []byte) string {
+	const maxLength = 200
+
+	summary := strings.Join(strings.Fields(string(body)), " ")
+	if len(summary) > maxLength {
+		return summary[:maxLength] + "..."
+	}
+	if summary == "" {
+		return "empty response"
+	}
+	return summary
+}
@@ -0,0 +1,382 @@
+package extractors_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/motain/of-catalog/internal/services/jsonservice"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExtractor_JSONAPIRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	mockConfig.EXPECT().Get("SONAR_USER").Return("user")
+	mockConfig.EXPECT().Get("SONAR_PASSWORD").Return("secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/measures", r.URL.Path)
+		assert.Equal(t, "my-service", r.URL.Query().Get("component"))
+		assert.Equal(t, "v1", r.Header.Get("X-Api-Version"))
+
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", username)
+		assert.Equal(t, "secret", password)
+
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"metricKeys": ["coverage"]}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"measures": [{"metric": "coverage", "value": "87.5"}]}`))
+	}))
+	defer server.Close()
+
+	ex := extractors.NewExtractor(mockConfig, jsonservice.NewJSONService(mockConfig), nil, nil, nil, nil, nil, nil)
+	task := &dtos.Task{
+		Source:         "jsonapi",
+		URI:            server.URL + "/api/measures",
+		HTTPMethod:     "post",
+		Body:           `{"metricKeys": ["coverage"]}`,
+		Headers:        map[string]string{"X-Api-Version": "v1"},
+		QueryParams:    map[string]string{"component": "my-service"},
+		ExpectedStatus: []int{http.StatusOK, http.StatusCreated},
+		Auth:           &dtos.TaskAuth{Type: "basic", UsernameVar: "SONAR_USER", PasswordVar: "SONAR_PASSWORD"},
+		Rule:           "jsonpath",
+		JSONPath:       ".measures[0].value",
+	}
+
+	assert.NoError(t, ex.Extract(context.Background(), task, nil))
+	assert.Equal(t, value.MustOf([]interface{}{"87.5"}), task.Result)
+}
+
+func TestExtractor_JSONAPIUnexpectedStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	mockConfig.EXPECT().Get("PD_TOKEN").Return("token")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ex := extractors.NewExtractor(mockConfig, jsonservice.NewJSONService(mockConfig), nil, nil, nil, nil, nil, nil)
+	task := &dtos.Task{
+		Source:         "jsonapi",
+		URI:            server.URL,
+		ExpectedStatus: []int{http.StatusOK},
+		Auth:           &dtos.TaskAuth{Type: "bearer", TokenVar: "PD_TOKEN"},
+	}
+
+	err := ex.Extract(context.Background(), task, nil)
+	assert.ErrorContains(t, err, "unexpected status code 403")
+}
+
+func TestExtractor_JSONAPIPagination(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4}, {5}}
+
+	tests := []struct {
+		name       string
+		pagination *dtos.TaskPagination
+		handler    func(w http.ResponseWriter, r *http.Request, serverURL string)
+		expected   []interface{}
+	}{
+		{
+			name:       "link header",
+			pagination: &dtos.TaskPagination{Type: "link", ItemsPath: ".items"},
+			handler: func(w http.ResponseWriter, r *http.Request, serverURL string) {
+				page := 0
+				_, _ = fmt.Sscanf(r.URL.Query().Get("p"), "%d", &page)
+				if page < len(pages)-1 {
+					w.Header().Set("Link", fmt.Sprintf(`<%s/incidents?p=%d>; rel="next", <%s/incidents?p=0>; rel="first"`, serverURL, page+1, serverURL))
+				}
+				_, _ = fmt.Fprintf(w, `{"items": %s}`, toJSON(pages[page]))
+			},
+			expected: []interface{}{1.0, 2.0, 3.0, 4.0, 5.0},
+		},
+		{
+			name:       "cursor",
+			pagination: &dtos.TaskPagination{Type: "cursor", ItemsPath: ".data", CursorPath: ".meta.next", CursorParam: "after"},
+			handler: func(w http.ResponseWriter, r *http.Request, _ string) {
+				page := 0
+				_, _ = fmt.Sscanf(r.URL.Query().Get("after"), "c%d", &page)
+				next := "null"
+				if page < len(pages)-1 {
+					next = fmt.Sprintf(`"c%d"`, page+1)
+				}
+				_, _ = fmt.Fprintf(w, `{"data": %s, "meta": {"next": %s}}`, toJSON(pages[page]), next)
+			},
+			expected: []interface{}{1.0, 2.0, 3.0, 4.0, 5.0},
+		},
+		{
+			name:       "page number stops on empty page",
+			pagination: &dtos.TaskPagination{Type: "page", PageParam: "p"},
+			handler: func(w http.ResponseWriter, r *http.Request, _ string) {
+				page := 0
+				_, _ = fmt.Sscanf(r.URL.Query().Get("p"), "%d", &page)
+				assert.Equal(t, "open", r.URL.Query().Get("status"))
+				if page > len(pages) {
+					_, _ = w.Write([]byte(`[]`))
+					return
+				}
+				_, _ = w.Write([]byte(toJSON(pages[page-1])))
+			},
+			expected: []interface{}{1.0, 2.0, 3.0, 4.0, 5.0},
+		},
+		{
+			name:       "zero-indexed page number",
+			pagination: &dtos.TaskPagination{Type: "page", PageParam: "p", StartPage: new(int)},
+			handler: func(w http.ResponseWriter, r *http.Request, _ string) {
+				page := -1
+				_, _ = fmt.Sscanf(r.URL.Query().Get("p"), "%d", &page)
+				if page < 0 || page >= len(pages) {
+					_, _ = w.Write([]byte(`[]`))
+					return
+				}
+				_, _ = w.Write([]byte(toJSON(pages[page])))
+			},
+			expected: []interface{}{1.0, 2.0, 3.0, 4.0, 5.0},
+		},
+		{
+			name:       "last page within max pages",
+			pagination: &dtos.TaskPagination{Type: "page", MaxPages: 2},
+			handler: func(w http.ResponseWriter, r *http.Request, _ string) {
+				if r.URL.Query().Get("page") == "2" {
+					_, _ = w.Write([]byte(`[]`))
+					return
+				}
+				_, _ = w.Write([]byte(`[1]`))
+			},
+			expected: []interface{}{1.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				tt.handler(w, r, server.URL)
+			}))
+			defer server.Close()
+
+			mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+			ex := extractors.NewExtractor(mockConfig, jsonservice.NewJSONService(mockConfig), nil, nil, nil, nil, nil, nil)
+			task := &dtos.Task{
+				Source:      "jsonapi",
+				URI:         server.URL + "/incidents",
+				QueryParams: map[string]string{"status": "open"},
+				Pagination:  tt.pagination,
+				Rule:        "jsonpath",
+				JSONPath:    ".[]",
+			}
+
+			assert.NoError(t, ex.Extract(context.Background(), task, nil))
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
+
+func TestExtractor_JSONAPIPaginationLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[1]`))
+	}))
+	defer server.Close()
+
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	ex := extractors.NewExtractor(mockConfig, jsonservice.NewJSONService(mockConfig), nil, nil, nil, nil, nil, nil)
+	task := &dtos.Task{
+		Source:     "jsonapi",
+		URI:        server.URL,
+		Pagination: &dtos.TaskPagination{Type: "page", MaxPages: 2},
+		Rule:       "jsonpath",
+		JSONPath:   ".[]",
+	}
+
+	err := ex.Extract(context.Background(), task, nil)
+	assert.ErrorContains(t, err, "more than 2 pages, raise maxPages")
+	assert.Equal(t, 2, requests)
+}
+
+func TestExtractor_JSONAPIBodyPlaceholder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	ex := extractors.NewExtractor(mockConfig, jsonservice.NewJSONService(mockConfig), nil, nil, nil, nil, nil, nil)
+	task := &dtos.Task{
+		Source:     "jsonapi",
+		URI:        server.URL + "/search",
+		HTTPMethod: "post",
+		Body:       `{"service": ":service"}`,
+		Rule:       "jsonpath",
+		JSONPath:   ".ok",
+	}
+	deps := []*dtos.Task{{ID: "services", Result: value.MustOf([]string{"api", "worker"})}}
+
+	assert.NoError(t, ex.Extract(context.Background(), task, deps))
+	assert.Equal(t, []string{`{"service": "api"}`, `{"service": "worker"}`}, bodies)
+	assert.Equal(t, `{"service": ":service"}`, task.Body)
+}
+
+func toJSON(values []int) string {
+	encoded, _ := json.Marshal(values)
+	return string(encoded)
+}
+
+func TestExtractor_JSONAPIResponseHandling(t *testing.T) {
+	tests := []struct {
+		name             string
+		responses        []func(w http.ResponseWriter)
+		task             dtos.Task
+		expectedResult   interface{}
+		expectedErr      string
+		expectedRequests int
+	}{
+		{
+			name: "retries server errors and rate limiting",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+				},
+				func(w http.ResponseWriter) {
+					w.Header().Set("Content-Type", "application/json; charset=utf-8")
+					_, _ = w.Write([]byte(`{"status": "ok"}`))
+				},
+			},
+			task:             dtos.Task{Retry: &dtos.TaskRetry{Attempts: 3, Backoff: "1ms"}},
+			expectedResult:   []interface{}{"ok"},
+			expectedRequests: 3,
+		},
+		{
+			name: "gives up after the last attempt",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusBadGateway)
+					_, _ = w.Write([]byte("upstream   unavailable"))
+				},
+			},
+			task:             dtos.Task{Retry: &dtos.TaskRetry{Attempts: 2, Backoff: "1ms"}},
+			expectedErr:      "unexpected status code 502 from GET",
+			expectedRequests: 2,
+		},
+		{
+			name: "non idempotent requests are not retried by default",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"status": "ok"}`))
+				},
+			},
+			task:             dtos.Task{HTTPMethod: "post", Body: `{"query": "open"}`},
+			expectedErr:      "unexpected status code 503 from POST",
+			expectedRequests: 1,
+		},
+		{
+			name: "client errors are not retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Content-Type", "text/html")
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = w.Write([]byte("<html>login</html>"))
+				},
+			},
+			task:             dtos.Task{Retry: &dtos.TaskRetry{Attempts: 3, Backoff: "1ms"}},
+			expectedErr:      "unexpected status code 401",
+			expectedRequests: 1,
+		},
+		{
+			name: "non JSON content type",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Content-Type", "text/html")
+					_, _ = w.Write([]byte("<html>maintenance</html>"))
+				},
+			},
+			expectedErr:      "unexpected content type text/html",
+			expectedRequests: 1,
+		},
+		{
+			name: "invalid JSON is reported instead of exiting",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"status": `))
+				},
+			},
+			expectedErr:      "invalid JSON data",
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.responses[min(requests, len(tt.responses)-1)](w)
+				requests++
+			}))
+			defer server.Close()
+
+			mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+			ex := extractors.NewExtractor(mockConfig, jsonservice.NewJSONService(mockConfig), nil, nil, nil, nil, nil, nil)
+			task := tt.task
+			task.Source = "jsonapi"
+			task.URI = server.URL
+			task.Rule = "jsonpath"
+			task.JSONPath = ".status"
+
+			err := ex.Extract(context.Background(), &task, nil)
+			assert.Equal(t, tt.expectedRequests, requests)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expectedResult), task.Result)
+		})
+	}
+}
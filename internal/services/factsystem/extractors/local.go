@@ -0,0 +1,35 @@
+package extractors
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+)
+
+// processLocal reads a file from the local directory of the task.
+// Like the GitHub source, a missing file is not an error and yields no data.
+func (ex *Extractor) processLocal(task *dtos.Task, result string) ([]byte, error) {
+	extractFilePath := utils.ReplacePlaceholder(task.FilePath, result)
+	fileContent, readErr := os.ReadFile(filepath.Join(localPath(task), extractFilePath))
+	if readErr != nil {
+		if errors.Is(readErr, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %v", extractFilePath, readErr)
+	}
+
+	return fileToJSON(task, extractFilePath, fileContent)
+}
+
+// localPath returns the directory read by local facts, defaulting to the working directory.
+func localPath(task *dtos.Task) string {
+	if task.Path == "" {
+		return "."
+	}
+	return task.Path
+}
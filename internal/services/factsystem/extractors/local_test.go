@@ -0,0 +1,118 @@
+package extractors_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExtractor_Local(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app.toml":                     "[service]\nreplicas_min = 3\n",
+		"package.json":                 `{"name": "my-service", "engines": {"node": "20"}}`,
+		".github/workflows/deploy.yml": "steps:\n  - uses: motain/onefootball-actions/paas-deploy@master\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "jsonpath on toml file",
+			task:     dtos.Task{FilePath: "app.toml", Rule: "jsonpath", JSONPath: ".service.replicas_min"},
+			expected: []interface{}{3.0},
+		},
+		{
+			name:     "jsonpath on json file",
+			task:     dtos.Task{FilePath: "package.json", Rule: "jsonpath", JSONPath: ".engines.node"},
+			expected: []interface{}{"20"},
+		},
+		{
+			name:     "notempty on existing file",
+			task:     dtos.Task{FilePath: "app.toml", Rule: "notempty"},
+			expected: true,
+		},
+		{
+			name:     "notempty on missing file",
+			task:     dtos.Task{FilePath: "Dockerfile", Rule: "notempty"},
+			expected: false,
+		},
+		{
+			name:     "raw content",
+			task:     dtos.Task{FilePath: "app.toml"},
+			expected: []byte("[service]\nreplicas_min = 3\n"),
+		},
+		{
+			name:     "search",
+			task:     dtos.Task{Rule: "search", SearchString: "paas-deploy@master"},
+			expected: true,
+		},
+		{
+			name:     "regex search with jsonpath",
+			task:     dtos.Task{Rule: "search", SearchString: `replicas_\w+ = \d`, SearchMode: "regex", JSONPath: ".[0].file"},
+			expected: []interface{}{"app.toml"},
+		},
+		{
+			name:        "unsupported extension with jsonpath",
+			task:        dtos.Task{FilePath: ".github/workflows/deploy.yml", Rule: "jsonpath", JSONPath: "."},
+			expectedErr: "unsupported file extension: .yml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, nil, nil, nil)
+			task := tt.task
+			task.Source = "local"
+			task.Path = root
+
+			err := ex.Extract(context.Background(), &task, nil)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
+
+func TestExtractor_LocalMultipleResults(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "api.json"), []byte(`{"replicas": 3, "public": true}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "worker.json"), []byte(`{"replicas": 1, "public": false}`), 0644))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, nil, nil, nil)
+	deps := []*dtos.Task{{ID: "services", Result: value.MustOf([]string{"api", "worker"})}}
+
+	replicas := &dtos.Task{Source: "local", Path: root, FilePath: ":service.json", Rule: "jsonpath", JSONPath: ".replicas"}
+	assert.NoError(t, ex.Extract(context.Background(), replicas, deps))
+	assert.Equal(t, value.MustOf([]float64{3, 1}), replicas.Result)
+
+	public := &dtos.Task{Source: "local", Path: root, FilePath: ":service.json", Rule: "jsonpath", JSONPath: ".public"}
+	assert.NoError(t, ex.Extract(context.Background(), public, deps))
+	assert.Equal(t, value.MustOf([]bool{true, false}), public.Result)
+}
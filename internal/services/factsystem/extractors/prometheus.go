@@ -0,0 +1,45 @@
+package extractors
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/services/prometheusservice"
+	"github.com/prometheus/common/model"
+)
+
+// queryPrometheus runs the fact query and encodes its result.
+// Range queries and jsonpath facts get the full result (labels and values of every series),
+// instant queries without jsonpath are reduced to a single number.
+func (ex *Extractor) queryPrometheus(task *dtos.Task, result string) ([]byte, error) {
+	prometheusQuery := utils.ReplacePlaceholder(task.PrometheusQuery, result)
+
+	var response model.Value
+	var queryErr error
+	if task.RangeQuery != nil {
+		start, end, step, rangeErr := prometheusservice.ParseRange(task.RangeQuery.Start, task.RangeQuery.End, task.RangeQuery.Step, time.Now())
+		if rangeErr != nil {
+			return nil, fmt.Errorf("invalid range query: %v", rangeErr)
+		}
+		response, queryErr = ex.prometheusService.RangeQuery(task.Datasource, prometheusQuery, start, end, step)
+	} else {
+		response, queryErr = ex.prometheusService.InstantQuery(task.Datasource, prometheusQuery)
+	}
+	if queryErr != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %v", queryErr)
+	}
+
+	if task.RangeQuery != nil || dtos.TaskRule(task.Rule) == dtos.JSONPathRule {
+		return prometheusservice.ToJSON(response)
+	}
+
+	scalar, scalarErr := prometheusservice.ToScalar(response)
+	if scalarErr != nil {
+		return nil, scalarErr
+	}
+
+	return json.Marshal(scalar)
+}
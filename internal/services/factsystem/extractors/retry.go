@@ -0,0 +1,122 @@
+package extractors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+)
+
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryBackoff    = time.Second
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+// retryPolicy is the resolved form of dtos.TaskRetry.
+type retryPolicy struct {
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// newRetryPolicy resolves the retry policy of a request. Requests without retry configuration are only
+// retried when the method is idempotent (GET, HEAD), replaying other requests may repeat their side effects.
+func newRetryPolicy(retry *dtos.TaskRetry, method string) (retryPolicy, error) {
+	policy := retryPolicy{
+		attempts:   defaultRetryAttempts,
+		backoff:    defaultRetryBackoff,
+		maxBackoff: defaultRetryMaxBackoff,
+	}
+	if retry == nil {
+		if method != http.MethodGet && method != http.MethodHead {
+			policy.attempts = 1
+		}
+		return policy, nil
+	}
+
+	if retry.Attempts > 0 {
+		policy.attempts = retry.Attempts
+	}
+
+	if retry.Backoff != "" {
+		backoff, parseErr := time.ParseDuration(retry.Backoff)
+		if parseErr != nil {
+			return retryPolicy{}, fmt.Errorf("invalid retry backoff %s: %v", retry.Backoff, parseErr)
+		}
+		policy.backoff = backoff
+	}
+
+	if retry.MaxBackoff != "" {
+		maxBackoff, parseErr := time.ParseDuration(retry.MaxBackoff)
+		if parseErr != nil {
+			return retryPolicy{}, fmt.Errorf("invalid retry maxBackoff %s: %v", retry.MaxBackoff, parseErr)
+		}
+		policy.maxBackoff = maxBackoff
+	}
+
+	return policy, nil
+}
+
+// delay returns how long to wait before the next attempt. The Retry-After header of the response
+// takes precedence over the exponential backoff, both are capped by maxBackoff.
+func (p retryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	shift := attempt - 1
+	delay := p.backoff << shift
+	if p.backoff > 0 && (shift >= 63 || delay>>shift != p.backoff) {
+		// the exponential backoff overflowed
+		delay = p.maxBackoff
+	}
+
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			delay = retryAfter
+		}
+	}
+
+	return min(delay, p.maxBackoff)
+}
+
+// shouldRetry reports whether a request failed transiently: network errors, rate limiting and server errors.
+// Cancelled requests are never retried. err is only an error of sending the request or reading the response,
+// requests which cannot be built are reported before the first attempt.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses a Retry-After header expressed either in seconds or as an HTTP date.
+func parseRetryAfter(retryAfter string, now time.Time) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+
+	if seconds, parseErr := strconv.Atoi(retryAfter); parseErr == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, parseErr := http.ParseTime(retryAfter); parseErr == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
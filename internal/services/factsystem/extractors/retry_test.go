@@ -0,0 +1,120 @@
+package extractors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/jsonservice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy, err := newRetryPolicy(&dtos.TaskRetry{Backoff: "100ms", MaxBackoff: "1s"}, http.MethodPost)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRetryAttempts, policy.attempts)
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		expected   time.Duration
+	}{
+		{name: "first attempt", attempt: 1, expected: 100 * time.Millisecond},
+		{name: "exponential backoff", attempt: 3, expected: 400 * time.Millisecond},
+		{name: "capped by max backoff", attempt: 10, expected: time.Second},
+		{name: "overflowing backoff", attempt: 70, expected: time.Second},
+		{name: "retry-after in seconds", attempt: 1, retryAfter: "0", expected: 0},
+		{name: "retry-after capped by max backoff", attempt: 1, retryAfter: "120", expected: time.Second},
+		{name: "invalid retry-after falls back to backoff", attempt: 2, retryAfter: "soon", expected: 200 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			assert.Equal(t, tt.expected, policy.delay(tt.attempt, resp))
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("Wed, 01 Jan 2025 12:00:30 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, delay)
+
+	delay, ok = parseRetryAfter("Wed, 01 Jan 2025 11:00:00 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+}
+
+func TestNewRetryPolicy_InvalidBackoff(t *testing.T) {
+	_, err := newRetryPolicy(&dtos.TaskRetry{Backoff: "fast"}, http.MethodGet)
+	assert.EqualError(t, err, "invalid retry backoff fast: time: invalid duration \"fast\"")
+}
+
+func TestRetryPolicy_ZeroBackoff(t *testing.T) {
+	policy, err := newRetryPolicy(&dtos.TaskRetry{Backoff: "0s"}, http.MethodGet)
+	assert.NoError(t, err)
+
+	for _, attempt := range []int{1, 2, 10} {
+		assert.Equal(t, time.Duration(0), policy.delay(attempt, nil))
+	}
+}
+
+func TestNewRetryPolicy_Defaults(t *testing.T) {
+	tests := []struct {
+		method   string
+		retry    *dtos.TaskRetry
+		expected int
+	}{
+		{method: http.MethodGet, expected: defaultRetryAttempts},
+		{method: http.MethodHead, expected: defaultRetryAttempts},
+		{method: http.MethodPost, expected: 1},
+		{method: http.MethodPatch, expected: 1},
+		{method: http.MethodPost, retry: &dtos.TaskRetry{}, expected: defaultRetryAttempts},
+		{method: http.MethodPut, retry: &dtos.TaskRetry{Attempts: 5}, expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			policy, err := newRetryPolicy(tt.retry, tt.method)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, policy.attempts)
+		})
+	}
+}
+
+func TestRequestJSONAPI_NotRetryingConfigurationErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	ex := NewExtractor(nil, jsonservice.NewJSONService(nil), nil, nil, nil, nil, nil, nil)
+	task := &dtos.Task{
+		URI:   server.URL,
+		Auth:  &dtos.TaskAuth{Type: "oauth"},
+		Retry: &dtos.TaskRetry{Attempts: 3, Backoff: "1m"},
+	}
+
+	// a retried request would wait for the backoff until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, _, err := ex.requestJSONAPI(ctx, task, task.URI, url.Values{})
+	assert.EqualError(t, err, "unknown auth type oauth")
+	assert.Equal(t, 0, requests)
+}
@@ -0,0 +1,79 @@
+package extractors
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/motain/of-catalog/internal/services/codesearchservice"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+)
+
+// processGithubSearch runs the search rule against the configured backend.
+// Without a jsonPath it reports whether anything matched, otherwise the jsonPath is applied
+// to the list of matches ({"file", "line", "text"}).
+func (ex *Extractor) processGithubSearch(task *dtos.Task) (interface{}, error) {
+	matches, searchErr := ex.searchCode(task)
+	if searchErr != nil {
+		return nil, fmt.Errorf("failed to process github Search request for source for string %s %s: %v", task.SearchString, task.Source, searchErr)
+	}
+
+	return searchResult(task, matches)
+}
+
+// processLocalSearch runs the search rule against the local directory of the task.
+func (ex *Extractor) processLocalSearch(task *dtos.Task) (interface{}, error) {
+	isRegex := dtos.TaskSearchMode(task.SearchMode) == dtos.RegexSearchMode
+	matches, searchErr := codesearchservice.ScanDir(localPath(task), task.SearchString, isRegex)
+	if searchErr != nil {
+		return nil, fmt.Errorf("failed to search %s in %s: %v", task.SearchString, localPath(task), searchErr)
+	}
+
+	return searchResult(task, matches)
+}
+
+// searchResult reports whether anything matched, or applies the jsonPath of the task to the matches.
+func searchResult(task *dtos.Task, matches []codesearchservice.Match) (interface{}, error) {
+	if task.JSONPath == "" {
+		return len(matches) != 0, nil
+	}
+
+	jsonData, marshalErr := json.Marshal(matches)
+	if marshalErr != nil {
+		return nil, fmt.Errorf("failed to marshal search matches: %v", marshalErr)
+	}
+
+	return utils.InspectExtractedData(task.JSONPath, jsonData)
+}
+
+func (ex *Extractor) searchCode(task *dtos.Task) ([]codesearchservice.Match, error) {
+	backend := dtos.TaskSearchBackend(task.SearchBackend)
+	if backend == "" {
+		backend = dtos.TaskSearchBackend(ex.config.GetSearchBackend())
+	}
+
+	isRegex := dtos.TaskSearchMode(task.SearchMode) == dtos.RegexSearchMode
+
+	switch backend {
+	case dtos.CloneSearchBackend:
+		return ex.codeSearch.Search(task.Repo, task.Branch, task.SearchString, isRegex)
+	case dtos.APISearchBackend:
+		if isRegex {
+			return nil, fmt.Errorf("search mode %s is not supported by the %s search backend", task.SearchMode, backend)
+		}
+
+		paths, searchErr := ex.github.Search(task.Repo, task.SearchString)
+		if searchErr != nil {
+			return nil, searchErr
+		}
+
+		// GitHub code search only reports the files containing the string
+		matches := make([]codesearchservice.Match, len(paths))
+		for i, path := range paths {
+			matches[i] = codesearchservice.Match{File: path}
+		}
+		return matches, nil
+	default:
+		return nil, fmt.Errorf("unknown search backend %s", backend)
+	}
+}
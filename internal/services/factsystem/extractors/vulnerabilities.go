@@ -0,0 +1,39 @@
+package extractors
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/utils/vulnreport"
+)
+
+// countVulnerabilities parses a vulnerability or SBOM report.
+// Without a jsonPath it returns the number of findings with at least the severity of the task,
+// otherwise the jsonPath is applied to the summary of the report.
+func countVulnerabilities(task *dtos.Task, report []byte) (interface{}, error) {
+	if report == nil {
+		return nil, errors.New("vulnerability report not found")
+	}
+
+	summary, parseErr := vulnreport.Parse(vulnreport.Format(task.ReportFormat), report)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	if task.JSONPath == "" {
+		count, countErr := summary.AtLeast(vulnreport.Severity(task.Severity))
+		if countErr != nil {
+			return nil, countErr
+		}
+		return float64(count), nil
+	}
+
+	summaryJSON, marshalErr := json.Marshal(summary)
+	if marshalErr != nil {
+		return nil, marshalErr
+	}
+
+	return utils.InspectExtractedData(task.JSONPath, summaryJSON)
+}
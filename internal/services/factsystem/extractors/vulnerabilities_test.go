@@ -0,0 +1,87 @@
+package extractors_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExtractor_Vulnerabilities(t *testing.T) {
+	root := t.TempDir()
+	report := `{
+	  "SchemaVersion": 2,
+	  "Results": [
+	    {
+	      "Target": "my-service:1.2.3 (alpine 3.19.1)",
+	      "Vulnerabilities": [
+	        {"VulnerabilityID": "CVE-2024-0001", "PkgName": "openssl", "InstalledVersion": "3.1.4", "FixedVersion": "3.1.5", "Severity": "CRITICAL"},
+	        {"VulnerabilityID": "CVE-2024-0002", "PkgName": "busybox", "InstalledVersion": "1.36.1", "Severity": "LOW"},
+	        {"VulnerabilityID": "CVE-2024-0003", "PkgName": "curl", "InstalledVersion": "8.5.0", "Severity": "HIGH"}
+	      ]
+	    }
+	  ]
+	}`
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "trivy.json"), []byte(report), 0644))
+
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "counts every finding by default",
+			task:     dtos.Task{FilePath: "trivy.json", Rule: "vulnerabilities"},
+			expected: 3.0,
+		},
+		{
+			name:     "counts findings of at least the given severity",
+			task:     dtos.Task{FilePath: "trivy.json", Rule: "vulnerabilities", ReportFormat: "trivy", Severity: "high"},
+			expected: 2.0,
+		},
+		{
+			name:     "jsonpath on the summary",
+			task:     dtos.Task{FilePath: "trivy.json", Rule: "vulnerabilities", JSONPath: `[.findings[] | select(.fixedVersion != null)] | length`},
+			expected: []interface{}{1},
+		},
+		{
+			name:        "wrong report format",
+			task:        dtos.Task{FilePath: "trivy.json", Rule: "vulnerabilities", ReportFormat: "sarif"},
+			expectedErr: `unknown report format "sarif"`,
+		},
+		{
+			name:        "missing report",
+			task:        dtos.Task{FilePath: "grype.json", Rule: "vulnerabilities"},
+			expectedErr: "vulnerability report not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ex := extractors.NewExtractor(configservice.NewMockConfigServiceInterface(ctrl), nil, nil, nil, nil, nil, nil, nil)
+			task := tt.task
+			task.Source = "local"
+			task.Path = root
+
+			err := ex.Extract(context.Background(), &task, nil)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -9,9 +10,12 @@ import (
 	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
 	"github.com/motain/of-catalog/internal/services/factsystem/extractors"
 	"github.com/motain/of-catalog/internal/services/factsystem/validators"
-	"github.com/motain/of-catalog/internal/utils/transformers"
 )
 
+// ErrNoResult is returned, wrapped with the id of the last fact, when the facts have no result (e.g. a failed
+// extraction). Callers report a failing 0, so that the metric does not keep the last value pushed.
+var ErrNoResult = errors.New("no result")
+
 type ProcessorInterface interface {
 	Process(ctx context.Context, tasks []*dtos.Task) (float64, error)
 }
@@ -44,7 +48,7 @@ func (p *Processor) Process(ctx context.Context, tasks []*dtos.Task) (float64, e
 		mappedTasks[task.ID] = task
 	}
 
-	var result interface{}
+	var last *dtos.Task
 	for _, task := range tasks {
 		task.DoneCh = make(chan dtos.TaskResult, 1)
 		for _, dependsOn := range task.DependsOn {
@@ -54,7 +58,7 @@ func (p *Processor) Process(ctx context.Context, tasks []*dtos.Task) (float64, e
 			task.Dependencies = append(task.Dependencies, mappedTasks[dependsOn])
 		}
 
-		go p.execute(ctx, task, &wg, &result)
+		go p.execute(ctx, task, &wg, &last)
 	}
 
 	wg.Wait()
@@ -62,11 +66,24 @@ func (p *Processor) Process(ctx context.Context, tasks []*dtos.Task) (float64, e
 	p.Mu.RLock()
 	defer p.Mu.RUnlock()
 
-	// Grab the result from the last task and try to convert it to a float64
-	return transformers.Interface2Float64(result)
+	if last == nil || last.Result.IsNull() {
+		lastID := ""
+		if last != nil {
+			lastID = last.ID
+		}
+		return 0, fmt.Errorf("fact %s: %w", lastID, ErrNoResult)
+	}
+
+	// Grab the result from the last task and convert it to a float64
+	metricValue, convErr := last.Result.ToNumber()
+	if convErr != nil {
+		return 0, fmt.Errorf("invalid metric result: %v", convErr)
+	}
+
+	return metricValue, nil
 }
 
-func (p *Processor) execute(ctx context.Context, task *dtos.Task, wg *sync.WaitGroup, result *interface{}) {
+func (p *Processor) execute(ctx context.Context, task *dtos.Task, wg *sync.WaitGroup, last **dtos.Task) {
 	defer wg.Done()
 	defer close(task.DoneCh)
 
@@ -98,14 +115,17 @@ func (p *Processor) execute(ctx context.Context, task *dtos.Task, wg *sync.WaitG
 	p.Mu.Lock()
 	defer p.Mu.Unlock()
 
-	*result = task.Result
+	*last = task
 	task.DoneCh <- dtos.TaskResult{Result: task.ID}
 }
 
 func (p *Processor) handleExtract(ctx context.Context, task *dtos.Task) error {
+	// forEach facts iterate over the dependency they name whatever its type,
+	// other facts get the results of their extract dependencies
 	var deps []*dtos.Task
 	for _, dep := range task.Dependencies {
-		if dtos.TaskType(dep.Type) == dtos.ExtractType {
+		if task.ForEach != nil && dep.ID == task.ForEach.Dependency ||
+			task.ForEach == nil && dtos.TaskType(dep.Type) == dtos.ExtractType {
 			deps = append(deps, dep)
 		}
 	}
@@ -0,0 +1,70 @@
+package processor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/aggregators"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	extractors "github.com/motain/of-catalog/internal/services/factsystem/extractors/mocks"
+	"github.com/motain/of-catalog/internal/services/factsystem/processor"
+	"github.com/motain/of-catalog/internal/services/factsystem/validators"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessor_Process(t *testing.T) {
+	tests := []struct {
+		name        string
+		extract     func(task *dtos.Task) error
+		expected    float64
+		expectedErr string
+	}{
+		{
+			name: "number result",
+			extract: func(task *dtos.Task) error {
+				task.Result = value.MustOf(3)
+				return nil
+			},
+			expected: 3,
+		},
+		{
+			name:        "failed extraction has no result",
+			extract:     func(task *dtos.Task) error { return errors.New("404 Not Found") },
+			expectedErr: "fact replicas: no result",
+		},
+		{
+			name: "list result",
+			extract: func(task *dtos.Task) error {
+				task.Result = value.MustOf([]int{1, 2})
+				return nil
+			},
+			expectedErr: "invalid metric result",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			extractor := extractors.NewMockExtractorInterface(ctrl)
+			extractor.EXPECT().Extract(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, task *dtos.Task, _ []*dtos.Task) error { return tt.extract(task) },
+			)
+
+			p := processor.NewProcessor(aggregators.NewAggregator(nil), validators.NewValidator(nil), extractor)
+			metricValue, err := p.Process(context.Background(), []*dtos.Task{{ID: "replicas", Type: "extract"}})
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				assert.Zero(t, metricValue)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, metricValue)
+		})
+	}
+}
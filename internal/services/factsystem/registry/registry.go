@@ -0,0 +1,206 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+)
+
+// Source fetches the data of extract tasks declaring it in their source property.
+type Source interface {
+	// Extract returns the raw data of the task, nil when there is nothing to extract (e.g. a missing file).
+	// dependencyResult holds the result of the dependency of the task, if any.
+	Extract(ctx context.Context, task *dtos.Task, dependencyResult string) ([]byte, error)
+}
+
+// RuleSource is implemented by sources applying some rules themselves instead of returning data,
+// e.g. the search rule of the GitHub source.
+type RuleSource interface {
+	Source
+	// ApplyRule returns the result of the task and true when the source handles the rule of the task.
+	ApplyRule(ctx context.Context, task *dtos.Task, dependencyResult string) (value.Value, bool, error)
+}
+
+// ExtractRule turns the data of a source into the result of an extract task.
+type ExtractRule interface {
+	Apply(ctx context.Context, task *dtos.Task, data []byte) (value.Value, error)
+}
+
+// ValidationRule computes the result of a validate task from its dependencies.
+type ValidationRule interface {
+	Check(task *dtos.Task, deps []*dtos.Task) (value.Value, error)
+}
+
+// Method computes the result of an aggregate task from its dependencies.
+type Method interface {
+	Combine(ctx context.Context, task *dtos.Task, deps []*dtos.Task) (value.Value, error)
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(ctx context.Context, task *dtos.Task, dependencyResult string) ([]byte, error)
+
+func (f SourceFunc) Extract(ctx context.Context, task *dtos.Task, dependencyResult string) ([]byte, error) {
+	return f(ctx, task, dependencyResult)
+}
+
+// ExtractRuleFunc adapts a function to the ExtractRule interface.
+type ExtractRuleFunc func(ctx context.Context, task *dtos.Task, data []byte) (value.Value, error)
+
+func (f ExtractRuleFunc) Apply(ctx context.Context, task *dtos.Task, data []byte) (value.Value, error) {
+	return f(ctx, task, data)
+}
+
+// ValidationRuleFunc adapts a function to the ValidationRule interface.
+type ValidationRuleFunc func(task *dtos.Task, deps []*dtos.Task) (value.Value, error)
+
+func (f ValidationRuleFunc) Check(task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+	return f(task, deps)
+}
+
+// MethodFunc adapts a function to the Method interface.
+type MethodFunc func(ctx context.Context, task *dtos.Task, deps []*dtos.Task) (value.Value, error)
+
+func (f MethodFunc) Combine(ctx context.Context, task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+	return f(ctx, task, deps)
+}
+
+// Registry holds the sources, rules and methods of the fact system by name.
+//
+// The built-in ones are registered by the extractor, the validator and the aggregator on the registry
+// provided to them. Registering a name twice panics so that an extension cannot silently replace a
+// built-in.
+type Registry struct {
+	mu              sync.RWMutex
+	sources         map[string]Source
+	extractRules    map[string]ExtractRule
+	validationRules map[string]ValidationRule
+	methods         map[string]Method
+}
+
+var (
+	extensionsMu sync.Mutex
+	extensions   []func(r *Registry)
+)
+
+// Extend adds a function registering in-house sources, rules or methods on every new registry.
+// Extension packages call it from their init function and are imported for their side effects, e.g.
+//
+//	func init() {
+//		registry.Extend(func(r *registry.Registry) {
+//			r.RegisterSource("backstage", registry.SourceFunc(fetchEntity))
+//		})
+//	}
+func Extend(extension func(r *Registry)) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+
+	extensions = append(extensions, extension)
+}
+
+// NewRegistry returns a registry holding the extensions added with Extend.
+func NewRegistry() *Registry {
+	r := &Registry{
+		sources:         make(map[string]Source),
+		extractRules:    make(map[string]ExtractRule),
+		validationRules: make(map[string]ValidationRule),
+		methods:         make(map[string]Method),
+	}
+
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	for _, extension := range extensions {
+		extension(r)
+	}
+
+	return r
+}
+
+// RegisterSource registers the source used by extract tasks with source: <name>.
+func (r *Registry) RegisterSource(name string, source Source) {
+	register(r, r.sources, "source", name, source)
+}
+
+// RegisterExtractRule registers the rule used by extract tasks with rule: <name>.
+func (r *Registry) RegisterExtractRule(name string, rule ExtractRule) {
+	register(r, r.extractRules, "extract rule", name, rule)
+}
+
+// RegisterValidationRule registers the rule used by validate tasks with rule: <name>.
+func (r *Registry) RegisterValidationRule(name string, rule ValidationRule) {
+	register(r, r.validationRules, "validation rule", name, rule)
+}
+
+// RegisterMethod registers the method used by aggregate tasks with method: <name>.
+func (r *Registry) RegisterMethod(name string, method Method) {
+	register(r, r.methods, "method", name, method)
+}
+
+func (r *Registry) Source(name string) (Source, bool) {
+	return lookup(r, r.sources, name)
+}
+
+func (r *Registry) ExtractRule(name string) (ExtractRule, bool) {
+	return lookup(r, r.extractRules, name)
+}
+
+func (r *Registry) ValidationRule(name string) (ValidationRule, bool) {
+	return lookup(r, r.validationRules, name)
+}
+
+func (r *Registry) Method(name string) (Method, bool) {
+	return lookup(r, r.methods, name)
+}
+
+// Sources returns the sorted names of the registered sources.
+func (r *Registry) Sources() []string {
+	return names(r, r.sources)
+}
+
+// ExtractRules returns the sorted names of the registered extract rules.
+func (r *Registry) ExtractRules() []string {
+	return names(r, r.extractRules)
+}
+
+// ValidationRules returns the sorted names of the registered validation rules.
+func (r *Registry) ValidationRules() []string {
+	return names(r, r.validationRules)
+}
+
+// Methods returns the sorted names of the registered methods.
+func (r *Registry) Methods() []string {
+	return names(r, r.methods)
+}
+
+func register[T any](r *Registry, entries map[string]T, kind, name string, entry T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := entries[name]; found {
+		panic(fmt.Sprintf("fact system %s %s is already registered", kind, name))
+	}
+	entries[name] = entry
+}
+
+func lookup[T any](r *Registry, entries map[string]T, name string) (T, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, found := entries[name]
+	return entry, found
+}
+
+func names[T any](r *Registry, entries map[string]T) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]string, 0, len(entries))
+	for name := range entries {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
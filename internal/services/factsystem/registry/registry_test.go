@@ -0,0 +1,63 @@
+package registry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/registry"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := registry.NewRegistry()
+	r.RegisterSource("backstage", registry.SourceFunc(func(_ context.Context, _ *dtos.Task, _ string) ([]byte, error) {
+		return []byte(`{"kind":"Component"}`), nil
+	}))
+	r.RegisterSource("artifactory", registry.SourceFunc(func(_ context.Context, _ *dtos.Task, _ string) ([]byte, error) {
+		return nil, nil
+	}))
+	r.RegisterMethod("median", registry.MethodFunc(func(_ context.Context, _ *dtos.Task, _ []*dtos.Task) (value.Value, error) {
+		return value.Number(1), nil
+	}))
+
+	source, found := r.Source("backstage")
+	assert.True(t, found)
+	data, err := source.Extract(context.Background(), &dtos.Task{}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"kind":"Component"}`, string(data))
+
+	_, found = r.Source("unknown")
+	assert.False(t, found)
+	_, found = r.ValidationRule("median")
+	assert.False(t, found)
+
+	assert.Equal(t, []string{"artifactory", "backstage"}, r.Sources())
+	assert.Equal(t, []string{"median"}, r.Methods())
+	assert.Empty(t, r.ExtractRules())
+
+	assert.PanicsWithValue(t, "fact system method median is already registered", func() {
+		r.RegisterMethod("median", registry.MethodFunc(func(_ context.Context, _ *dtos.Task, _ []*dtos.Task) (value.Value, error) {
+			return value.Null, nil
+		}))
+	})
+}
+
+func TestExtend(t *testing.T) {
+	registry.Extend(func(r *registry.Registry) {
+		r.RegisterValidationRule("test_extension", registry.ValidationRuleFunc(func(_ *dtos.Task, _ []*dtos.Task) (value.Value, error) {
+			return value.Bool(true), nil
+		}))
+	})
+
+	rule, found := registry.NewRegistry().ValidationRule("test_extension")
+	assert.True(t, found)
+	result, err := rule.Check(&dtos.Task{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, value.Bool(true), result)
+
+	// Every registry gets its own copy of the extensions
+	_, found = registry.NewRegistry().ValidationRule("test_extension")
+	assert.True(t, found)
+}
@@ -2,7 +2,7 @@ package utils
 
 import (
 	"encoding/json"
-	"log"
+	"fmt"
 	"regexp"
 
 	"github.com/itchyny/gojq"
@@ -16,7 +16,7 @@ func InspectExtractedData(JSONPath string, jsonData []byte) (interface{}, error)
 
 	var data interface{}
 	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
-		log.Fatal(err)
+		return nil, fmt.Errorf("invalid JSON data: %v", err)
 	}
 
 	res := make([]interface{}, 0)
@@ -1,14 +1,122 @@
 package utils
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
 )
 
-const placeholderPattern = `:[a-zA-Z_]+`
+// variablePattern matches the {{name}} and {{name.path}} templates of forEach facts.
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)((?:\.[A-Za-z0-9_-]+)*)\s*\}\}`)
 
+// ReplacePlaceholder replaces the legacy :name placeholders of facts depending on a list with value.
+// A placeholder starts a token: the colon follows the start of the string or a character that is not
+// part of a name, host or scheme, so ports (host:8080), schemes (https://) and names such as
+// job:rate5m are kept.
+//
+// Deprecated: facts should iterate explicitly with forEach and reference the item with {{item}}.
 func ReplacePlaceholder(target, value string) string {
-	re := regexp.MustCompile(placeholderPattern)
-	return re.ReplaceAllStringFunc(target, func(match string) string {
-		return value
+	var replaced strings.Builder
+	for i := 0; i < len(target); i++ {
+		end := placeholderEnd(target, i)
+		if end == i {
+			replaced.WriteByte(target[i])
+			continue
+		}
+
+		replaced.WriteString(value)
+		i = end - 1
+	}
+	return replaced.String()
+}
+
+// placeholderEnd returns the end of the placeholder starting at i, or i when there is none.
+func placeholderEnd(target string, i int) int {
+	if target[i] != ':' || (i > 0 && isNameChar(target[i-1])) {
+		return i
+	}
+
+	end := i + 1
+	for end < len(target) && (isLetter(target[end]) || target[end] == '_') {
+		end++
+	}
+	if end == i+1 {
+		return i
+	}
+	return end
+}
+
+func isNameChar(c byte) bool {
+	return isLetter(c) || (c >= '0' && c <= '9') || c == '_' || c == '.' || c == ':' || c == '-'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// ReplaceVariables renders the {{name}} templates of target. Paths select fields of objects and
+// items of lists ({{item.id}}, {{item.tags.0}}) and must end on a scalar.
+func ReplaceVariables(target string, variables map[string]value.Value) (string, error) {
+	var renderErr error
+	rendered := variablePattern.ReplaceAllStringFunc(target, func(match string) string {
+		groups := variablePattern.FindStringSubmatch(match)
+		variable, found := variables[groups[1]]
+		if !found {
+			if renderErr == nil {
+				renderErr = fmt.Errorf("unknown variable %s", groups[1])
+			}
+			return match
+		}
+
+		text, lookupErr := lookupVariable(variable, groups[2])
+		if lookupErr != nil && renderErr == nil {
+			renderErr = fmt.Errorf("variable %s%s: %v", groups[1], groups[2], lookupErr)
+		}
+		return text
 	})
+
+	return rendered, renderErr
+}
+
+// TemplateVariables returns the names of the variables referenced by the templates of target.
+func TemplateVariables(target string) []string {
+	matches := variablePattern.FindAllStringSubmatch(target, -1)
+	names := make([]string, len(matches))
+	for i, match := range matches {
+		names[i] = match[1]
+	}
+	return names
+}
+
+func lookupVariable(variable value.Value, path string) (string, error) {
+	current := variable
+	for _, key := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if key == "" {
+			continue
+		}
+
+		switch current.Kind() {
+		case value.ObjectKind:
+			fields, _ := current.Object()
+			field, found := fields[key]
+			if !found {
+				return "", fmt.Errorf("unknown field %s", key)
+			}
+			current = field
+		case value.ListKind:
+			items, _ := current.List()
+			index, indexErr := strconv.Atoi(key)
+			if indexErr != nil || index < 0 || index >= len(items) {
+				return "", fmt.Errorf("invalid index %s of a list of %d items", key, len(items))
+			}
+			current = items[index]
+		default:
+			return "", fmt.Errorf("cannot select %s of %s", key, current.Kind())
+		}
+	}
+
+	return current.ToString()
 }
@@ -0,0 +1,65 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplacePlaceholder(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{name: "path segment", target: "https://api.example.com/slos/:slo_id", expected: "https://api.example.com/slos/42"},
+		{name: "query value", target: "/alerts?id=:alert_id&limit=1", expected: "/alerts?id=42&limit=1"},
+		{name: "start of the string", target: ":service.json", expected: "42.json"},
+		{name: "port", target: "http://prometheus:9090/api/:id", expected: "http://prometheus:9090/api/42"},
+		{name: "named port", target: "http://prometheus:http/api", expected: "http://prometheus:http/api"},
+		{name: "recording rule", target: `job:http_requests:rate5m{service=":name"}`, expected: `job:http_requests:rate5m{service="42"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, utils.ReplacePlaceholder(tt.target, "42"))
+		})
+	}
+}
+
+func TestReplaceVariables(t *testing.T) {
+	variables := map[string]value.Value{
+		"item":  value.String("api"),
+		"slo":   value.MustOf(map[string]interface{}{"id": 7, "tags": []string{"tier-1"}}),
+		"ratio": value.Number(0.5),
+	}
+
+	tests := []struct {
+		name        string
+		target      string
+		expected    string
+		expectedErr string
+	}{
+		{name: "variable", target: "https://{{item}}.example.com:8443/health", expected: "https://api.example.com:8443/health"},
+		{name: "spaces and fields", target: "/slos/{{ slo.id }}?tag={{slo.tags.0}}&r={{ratio}}", expected: "/slos/7?tag=tier-1&r=0.5"},
+		{name: "no templates", target: `{"query": "{ a }"}`, expected: `{"query": "{ a }"}`},
+		{name: "unknown variable", target: "{{name}}", expectedErr: "unknown variable name"},
+		{name: "index out of range", target: "{{slo.tags.1}}", expectedErr: "variable slo.tags.1: invalid index 1 of a list of 1 items"},
+		{name: "not a scalar", target: "{{slo.tags}}", expectedErr: "variable slo.tags: expected a string, got a list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := utils.ReplaceVariables(tt.target, variables)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// CompileSchema compiles a JSON schema defined inline or in a JSON or YAML file.
+// Exactly one of inline and file must be set. References to other files are resolved relative to
+// the schema file, remote references are not supported.
+//
+// Parameters:
+//   - inline: The schema as written in the fact definition
+//   - file: The path of the schema file
+//
+// Returns:
+//   - *jsonschema.Schema: The compiled schema
+//   - error: Returned when no schema or both are set, when the file cannot be read or the schema is invalid
+func CompileSchema(inline map[string]interface{}, file string) (*jsonschema.Schema, error) {
+	if (inline == nil) == (file == "") {
+		return nil, errors.New("exactly one of schema and schemaFile must be set")
+	}
+
+	if file == "" {
+		encoded, marshalErr := json.Marshal(inline)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("invalid schema: %v", marshalErr)
+		}
+		schema, compileErr := jsonschema.CompileString("inline://schema.json", string(encoded))
+		if compileErr != nil {
+			return nil, fmt.Errorf("invalid schema: %v", compileErr)
+		}
+		return schema, nil
+	}
+
+	content, readErr := os.ReadFile(file)
+	if readErr != nil {
+		return nil, fmt.Errorf("failed to read schema: %v", readErr)
+	}
+
+	document, parseErr := ParseDocument(content)
+	if parseErr != nil {
+		return nil, fmt.Errorf("invalid schema %s: %v", file, parseErr)
+	}
+
+	encoded, marshalErr := json.Marshal(document)
+	if marshalErr != nil {
+		return nil, fmt.Errorf("invalid schema %s: %v", file, marshalErr)
+	}
+
+	absolute, absErr := filepath.Abs(file)
+	if absErr != nil {
+		return nil, absErr
+	}
+	schema, compileErr := jsonschema.CompileString(absolute, string(encoded))
+	if compileErr != nil {
+		return nil, fmt.Errorf("invalid schema %s: %v", file, compileErr)
+	}
+	return schema, nil
+}
+
+// ParseDocument decodes a JSON or YAML document into JSON compatible values, numbers are json.Number.
+func ParseDocument(content []byte) (interface{}, error) {
+	var document interface{}
+	if !json.Valid(content) {
+		if yamlErr := yaml.Unmarshal(content, &document); yamlErr != nil {
+			return nil, yamlErr
+		}
+
+		// Round trip through JSON so that YAML values get the types expected by the schema validator
+		encoded, marshalErr := json.Marshal(document)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		content = encoded
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if decodeErr := decoder.Decode(&document); decodeErr != nil {
+		return nil, decodeErr
+	}
+	return document, nil
+}
@@ -4,19 +4,36 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sync"
 
 	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/registry"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
 	"github.com/motain/of-catalog/internal/utils/eval"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 )
 
 type ValidatorInterface interface {
 	Check(task *dtos.Task, deps []*dtos.Task) error
 }
 
-type Validator struct{}
+type Validator struct {
+	registry *registry.Registry
 
-func NewValidator() *Validator {
-	return &Validator{}
+	// compiled json_schema schemas, so that schemas are compiled (and schema files read) once per run
+	schemasMu sync.Mutex
+	schemas   map[string]*jsonschema.Schema
+}
+
+func NewValidator(reg *registry.Registry) *Validator {
+	if reg == nil {
+		reg = registry.NewRegistry()
+	}
+
+	fc := &Validator{registry: reg, schemas: make(map[string]*jsonschema.Schema)}
+	fc.registerBuiltins()
+
+	return fc
 }
 
 func (fc *Validator) Check(task *dtos.Task, deps []*dtos.Task) error {
@@ -28,91 +45,127 @@ func (fc *Validator) Check(task *dtos.Task, deps []*dtos.Task) error {
 		return errors.New("too few dependencies provided for validate task")
 	}
 
-	if len(deps) > 1 {
-		return fc.validateDepsRelations(task, deps)
-	}
-
-	dep := deps[0]
-	if dep.Result == nil {
-		// Should I fail or ignore and set task.Result = false ?
-		return errors.New("dependency result not provided")
-	}
-
-	if values, isStringSlice := dep.Result.([]interface{}); isStringSlice {
-		err := fc.validateList(task, values)
-		if err != nil {
-			return err
-		}
-
-		return nil
+	rule, found := fc.registry.ValidationRule(task.Rule)
+	if !found {
+		return errors.New("unknown validation rule")
 	}
 
-	isValid, err := fc.validate(task, dep.Result)
+	result, err := rule.Check(task, deps)
 	if err != nil {
 		return err
 	}
 
-	task.Result = isValid
+	task.Result = result
 
 	return nil
 }
 
-func (fc *Validator) validateDepsRelations(task *dtos.Task, deps []*dtos.Task) error {
-	switch dtos.TaskRule(task.Rule) {
-	case dtos.DepsMatchRule:
-		return fc.validateDependeciesMatch(task, deps)
-	default:
-		return nil
-	}
+// registerBuiltins registers the validation rules shipped with the fact system.
+func (fc *Validator) registerBuiltins() {
+	fc.registry.RegisterValidationRule(string(dtos.RegexMatchRule), textRule(fc.validateRegex))
+	fc.registry.RegisterValidationRule(string(dtos.FormulaRule), valueRule(fc.validateFormula))
+	fc.registry.RegisterValidationRule(string(dtos.SemverRule), textRule(fc.validateSemver))
+	fc.registry.RegisterValidationRule(string(dtos.JSONSchemaRule), valueRule(fc.validateJSONSchema))
+	fc.registry.RegisterValidationRule(string(dtos.AgeRule), textRule(fc.validateAge))
+	fc.registry.RegisterValidationRule(string(dtos.UniqueRule), registry.ValidationRuleFunc(fc.validateUnique))
+	fc.registry.RegisterValidationRule(string(dtos.DepsMatchRule), relationRule(fc.validateDependeciesMatch))
+	fc.registry.RegisterValidationRule(string(dtos.SubsetOfRule), relationRule(fc.validateSubsetOf))
+	fc.registry.RegisterValidationRule(string(dtos.IntersectsRule), relationRule(fc.validateIntersects))
 }
 
-func (fc *Validator) validateList(task *dtos.Task, list []interface{}) error {
-	switch dtos.TaskRule(task.Rule) {
-	case dtos.UniqueRule:
-		return fc.validateUnique(task, list)
-	default:
-		return fc.validateEach(task, list)
-	}
-}
+// valueRule adapts a rule checking a single value to the registry. The rule applies to the result
+// of the single dependency of the task, or to every item when the result is a list. The results of
+// forEach facts are checked item by item and the rule returns an object keyed by the same items.
+func valueRule(check func(task *dtos.Task, v value.Value) (bool, error)) registry.ValidationRule {
+	return registry.ValidationRuleFunc(func(task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		// Value rules do not relate several dependencies
+		if len(deps) > 1 {
+			return value.Null, nil
+		}
 
-func (fc *Validator) validateUnique(task *dtos.Task, list []interface{}) error {
-	uniqueMap := make(map[interface{}]bool)
-	for _, v := range list {
-		if _, ok := uniqueMap[v]; ok {
-			task.Result = false
-			return nil
+		dep := deps[0]
+		if dep.Result.IsNull() {
+			// Should I fail or ignore and set task.Result = false ?
+			return value.Null, errors.New("dependency result not provided")
 		}
-		uniqueMap[v] = true
-	}
 
-	task.Result = true
-	return nil
+		if dep.ForEach == nil {
+			return checkValue(task, dep.Result, check)
+		}
+
+		fields, objectErr := dep.Result.Object()
+		if objectErr != nil {
+			return value.Null, fmt.Errorf("invalid forEach result: %v", objectErr)
+		}
+
+		res := make(map[string]value.Value, len(fields))
+		for key, field := range fields {
+			checked, err := checkValue(task, field, check)
+			if err != nil {
+				return value.Null, err
+			}
+			res[key] = checked
+		}
+
+		return value.Object(res), nil
+	})
 }
 
-func (fc *Validator) validateEach(task *dtos.Task, list []interface{}) error {
-	res := make([]bool, len(list))
-	for i, v := range list {
-		ok, err := fc.validate(task, v)
+func checkValue(task *dtos.Task, result value.Value, check func(task *dtos.Task, v value.Value) (bool, error)) (value.Value, error) {
+	values, listErr := result.List()
+	if listErr != nil {
+		ok, err := check(task, result)
+		return value.Bool(ok), err
+	}
+
+	res := make([]value.Value, len(values))
+	for i, v := range values {
+		ok, err := check(task, v)
 		if err != nil {
-			return err
+			return value.Null, err
 		}
-		res[i] = ok
+		res[i] = value.Bool(ok)
 	}
 
-	task.Result = res
-	return nil
+	return value.List(res...), nil
+}
+
+// textRule adapts a rule checking strings to the registry, numbers and booleans are converted to
+// strings while lists of lists and objects are rejected.
+func textRule(check func(task *dtos.Task, text string) (bool, error)) registry.ValidationRule {
+	return valueRule(func(task *dtos.Task, v value.Value) (bool, error) {
+		text, convErr := v.ToString()
+		if convErr != nil {
+			return false, fmt.Errorf("rule %s: %v", task.Rule, convErr)
+		}
+
+		return check(task, text)
+	})
 }
 
-func (fc *Validator) validate(task *dtos.Task, value interface{}) (bool, error) {
-	strValue := fmt.Sprintf("%v", value)
-	switch dtos.TaskRule(task.Rule) {
-	case dtos.RegexMatchRule:
-		return fc.validateRegex(task, strValue)
-	case dtos.FormulaRule:
-		return fc.validateFormula(task, strValue)
-	default:
-		return false, errors.New("unknown validation rule")
+func (fc *Validator) validateUnique(task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+	if len(deps) > 1 {
+		return value.Null, nil
+	}
+
+	if deps[0].Result.IsNull() {
+		return value.Null, errors.New("dependency result not provided")
 	}
+
+	list, listErr := deps[0].Result.List()
+	if listErr != nil {
+		return value.Null, fmt.Errorf("rule %s: %v", task.Rule, listErr)
+	}
+
+	uniqueMap := make(map[string]bool)
+	for _, v := range list {
+		if _, ok := uniqueMap[v.Key()]; ok {
+			return value.Bool(false), nil
+		}
+		uniqueMap[v.Key()] = true
+	}
+
+	return value.Bool(true), nil
 }
 
 func (fc *Validator) validateRegex(task *dtos.Task, value string) (bool, error) {
@@ -124,41 +177,11 @@ func (fc *Validator) validateRegex(task *dtos.Task, value string) (bool, error)
 	return regexPattern.MatchString(value), nil
 }
 
-func (fc *Validator) validateFormula(task *dtos.Task, value string) (bool, error) {
-	return eval.Expression(fmt.Sprintf("%s %s", value, task.Pattern))
-}
-
-func (fc *Validator) validateDependeciesMatch(task *dtos.Task, deps []*dtos.Task) error {
-	mappedDepResultsTypes := make(map[string][]*dtos.Task)
-	for _, dep := range deps {
-		resType := fmt.Sprintf("%T", dep.Result)
-		mappedDepResultsTypes[resType] = append(mappedDepResultsTypes[resType], dep)
+func (fc *Validator) validateFormula(task *dtos.Task, v value.Value) (bool, error) {
+	formula, parseErr := eval.Formula(task.Pattern)
+	if parseErr != nil {
+		return false, fmt.Errorf("invalid formula %q: %v", task.Pattern, parseErr)
 	}
 
-	if len(mappedDepResultsTypes) > 1 {
-		task.Result = false
-		return nil
-	}
-
-	if _, isSLice := deps[0].Result.([]interface{}); isSLice {
-		// Implement slice comparison
-		// mappedDepResultsLength := make(map[int][]*dtos.Task)
-		// for _, dep := range deps {
-		// 	length := len(dep.Result.([]interface{}))
-		// 	mappedDepResultsLength[length] = append(mappedDepResultsLength[length], dep)
-		// }
-		// if len(mappedDepResultsLength) > 1 {
-		// 	task.Result = false
-		// 	return nil
-		// }
-		return errors.New("slice comparison not implemented")
-	}
-
-	resultsMatch := true
-	for _, dep := range deps {
-		resultsMatch = resultsMatch && dep.Result != deps[0].Result
-	}
-
-	task.Result = resultsMatch
-	return nil
+	return eval.EvalFormula(formula, v.Interface())
 }
@@ -0,0 +1,361 @@
+package validators_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/validators"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator_CheckFormula(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		depResult   interface{}
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:      "partial formula on a number",
+			pattern:   "< 1",
+			depResult: 0.0,
+			expected:  true,
+		},
+		{
+			name:      "formula on every item of a list",
+			pattern:   ">= 2 && value <= 6",
+			depResult: []interface{}{3.0, 1.0, "6"},
+			expected:  []bool{true, false, true},
+		},
+		{
+			name:      "string equality",
+			pattern:   `== "production ready"`,
+			depResult: "production ready",
+			expected:  true,
+		},
+		{
+			name:        "invalid formula",
+			pattern:     ">= 2 and",
+			depResult:   3.0,
+			expectedErr: `invalid formula ">= 2 and": syntax error at position 8: unexpected end of expression`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &dtos.Task{Type: "validate", Rule: "formula", Pattern: tt.pattern}
+
+			err := validators.NewValidator(nil).Check(task, []*dtos.Task{{ID: "dep", Result: value.MustOf(tt.depResult)}})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
+
+func TestValidator_CheckRelations(t *testing.T) {
+	deps := func(results ...interface{}) []*dtos.Task {
+		tasks := make([]*dtos.Task, len(results))
+		for i, result := range results {
+			tasks[i] = &dtos.Task{ID: fmt.Sprintf("dep-%d", i), Result: value.MustOf(result)}
+		}
+		return tasks
+	}
+
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		deps        []*dtos.Task
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:     "deps_match on matching scalars",
+			task:     dtos.Task{Rule: "deps_match"},
+			deps:     deps("1.4.2", "1.4.2", "1.4.2"),
+			expected: true,
+		},
+		{
+			name:     "deps_match on different scalars",
+			task:     dtos.Task{Rule: "deps_match"},
+			deps:     deps("1.4.2", "1.4.3"),
+			expected: false,
+		},
+		{
+			name:     "deps_match compares numbers regardless of their type",
+			task:     dtos.Task{Rule: "deps_match"},
+			deps:     deps(3, 3.0),
+			expected: true,
+		},
+		{
+			name:     "deps_match on jsonpath and extract results",
+			task:     dtos.Task{Rule: "deps_match"},
+			deps:     deps([]interface{}{"1.4.2"}, []string{"1.4.2"}),
+			expected: true,
+		},
+		{
+			name:     "deps_match on lists in a different order",
+			task:     dtos.Task{Rule: "deps_match"},
+			deps:     deps([]interface{}{"a", "b"}, []interface{}{"b", "a"}),
+			expected: false,
+		},
+		{
+			name:     "deps_match on lists as sets",
+			task:     dtos.Task{Rule: "deps_match", MatchMode: "set"},
+			deps:     deps([]interface{}{"a", "b", "a"}, []interface{}{"b", "a"}),
+			expected: true,
+		},
+		{
+			name:     "deps_match on maps",
+			task:     dtos.Task{Rule: "deps_match"},
+			deps:     deps(map[string]interface{}{"cpu": 1.0, "memory": "1Gi"}, map[string]string{"memory": "1Gi", "cpu": "1"}),
+			expected: false,
+		},
+		{
+			name:     "deps_match on equal maps",
+			task:     dtos.Task{Rule: "deps_match"},
+			deps:     deps(map[string]interface{}{"cpu": "1", "memory": "1Gi"}, map[string]string{"memory": "1Gi", "cpu": "1"}),
+			expected: true,
+		},
+		{
+			name:        "deps_match with an unknown match mode",
+			task:        dtos.Task{Rule: "deps_match", MatchMode: "fuzzy"},
+			deps:        deps("a", "a"),
+			expectedErr: "unknown match mode fuzzy",
+		},
+		{
+			name:        "deps_match with a single dependency",
+			task:        dtos.Task{Rule: "deps_match"},
+			deps:        deps([]interface{}{"a"}),
+			expectedErr: "rule deps_match expects at least 2 dependencies, got 1",
+		},
+		{
+			name:     "subset_of",
+			task:     dtos.Task{Rule: "subset_of"},
+			deps:     deps([]interface{}{"eu-west-1"}, []interface{}{"eu-west-1", "eu-central-1"}),
+			expected: true,
+		},
+		{
+			name:     "subset_of with a missing item",
+			task:     dtos.Task{Rule: "subset_of"},
+			deps:     deps([]string{"eu-west-1", "us-east-1"}, []interface{}{"eu-west-1", "eu-central-1"}),
+			expected: false,
+		},
+		{
+			name:     "subset_of with a scalar",
+			task:     dtos.Task{Rule: "subset_of"},
+			deps:     deps("go", []interface{}{"go", "python"}),
+			expected: true,
+		},
+		{
+			name:        "subset_of with three dependencies",
+			task:        dtos.Task{Rule: "subset_of"},
+			deps:        deps("a", "a", "a"),
+			expectedErr: "rule subset_of expects 2 dependencies, got 3",
+		},
+		{
+			name:     "intersects",
+			task:     dtos.Task{Rule: "intersects"},
+			deps:     deps([]interface{}{"a", "b"}, []interface{}{"c", "b"}, []string{"b"}),
+			expected: true,
+		},
+		{
+			name:     "intersects without common items",
+			task:     dtos.Task{Rule: "intersects"},
+			deps:     deps([]interface{}{"a", "b"}, []interface{}{"c"}),
+			expected: false,
+		},
+		{
+			name:        "missing dependency result",
+			task:        dtos.Task{Rule: "intersects"},
+			deps:        deps([]interface{}{"a"}, nil),
+			expectedErr: "dependency result not provided",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.Type = "validate"
+
+			err := validators.NewValidator(nil).Check(&task, tt.deps)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
+
+func TestValidator_CheckRules(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "schema.yaml")
+	assert.NoError(t, os.WriteFile(schemaFile, []byte("type: object\nrequired: [service]\nproperties:\n  service:\n    type: object\n    required: [replicas_min]\n"), 0644))
+
+	inlineSchema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"name"},
+		"properties": map[string]interface{}{
+			"name":    map[string]interface{}{"type": "string"},
+			"version": map[string]interface{}{"type": "integer", "minimum": 2},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		task        dtos.Task
+		depResult   interface{}
+		expected    interface{}
+		expectedErr string
+	}{
+		{
+			name:      "semver constraint satisfied",
+			task:      dtos.Task{Rule: "semver", Pattern: ">= 1.22"},
+			depResult: "1.22.3",
+			expected:  true,
+		},
+		{
+			name:      "semver with prefixes and partial versions",
+			task:      dtos.Task{Rule: "semver", Pattern: ">= 1.22, < 2"},
+			depResult: []interface{}{"go1.23", "v1.21.9", "1.22"},
+			expected:  []bool{true, false, true},
+		},
+		{
+			name:        "semver with an invalid version",
+			task:        dtos.Task{Rule: "semver", Pattern: ">= 1.22"},
+			depResult:   "latest",
+			expectedErr: `invalid version "latest"`,
+		},
+		{
+			name:        "semver with an invalid constraint",
+			task:        dtos.Task{Rule: "semver", Pattern: ">== 1"},
+			depResult:   "1.0.0",
+			expectedErr: `invalid semver constraint ">== 1"`,
+		},
+		{
+			name:      "json_schema on raw content",
+			task:      dtos.Task{Rule: "json_schema", Schema: inlineSchema},
+			depResult: []byte(`{"name": "my-service", "version": 3}`),
+			expected:  true,
+		},
+		{
+			name:      "json_schema on an invalid document",
+			task:      dtos.Task{Rule: "json_schema", Schema: inlineSchema},
+			depResult: []byte(`{"name": "my-service", "version": 1.5}`),
+			expected:  false,
+		},
+		{
+			name:      "json_schema on yaml content with a schema file",
+			task:      dtos.Task{Rule: "json_schema", SchemaFile: schemaFile},
+			depResult: "service:\n  replicas_min: 3\n",
+			expected:  true,
+		},
+		{
+			name:      "json_schema on jsonpath results",
+			task:      dtos.Task{Rule: "json_schema", SchemaFile: schemaFile},
+			depResult: []interface{}{map[string]interface{}{"service": map[string]interface{}{"replicas_min": 3.0}}, map[string]interface{}{"service": map[string]interface{}{}}},
+			expected:  []bool{true, false},
+		},
+		{
+			name:        "json_schema without schema",
+			task:        dtos.Task{Rule: "json_schema"},
+			depResult:   "{}",
+			expectedErr: "exactly one of schema and schemaFile must be set",
+		},
+		{
+			name:      "age of a recent timestamp",
+			task:      dtos.Task{Rule: "age", MaxAge: "90d"},
+			depResult: time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+			expected:  true,
+		},
+		{
+			name:      "age of an old date",
+			task:      dtos.Task{Rule: "age", MaxAge: "90d"},
+			depResult: time.Now().AddDate(0, 0, -91).Format(time.DateOnly),
+			expected:  false,
+		},
+		{
+			name:      "age of a unix timestamp",
+			task:      dtos.Task{Rule: "age", MaxAge: "1h"},
+			depResult: float64(time.Now().Add(-30 * time.Minute).Unix()),
+			expected:  true,
+		},
+		{
+			name:        "age with an invalid timestamp",
+			task:        dtos.Task{Rule: "age", MaxAge: "1h"},
+			depResult:   "yesterday",
+			expectedErr: `invalid timestamp "yesterday"`,
+		},
+		{
+			name:        "age with an invalid max age",
+			task:        dtos.Task{Rule: "age", MaxAge: "3 months"},
+			depResult:   "2025-01-01",
+			expectedErr: `invalid max age "3 months"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.Type = "validate"
+
+			err := validators.NewValidator(nil).Check(&task, []*dtos.Task{{ID: "dep", Result: value.MustOf(tt.depResult)}})
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, value.MustOf(tt.expected), task.Result)
+		})
+	}
+}
+
+func TestValidator_CheckJSONSchemaCompiledOnce(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	assert.NoError(t, os.WriteFile(schemaFile, []byte(`{"type": "object", "required": ["name"]}`), 0644))
+
+	validator := validators.NewValidator(nil)
+	deps := []*dtos.Task{{ID: "dep", Result: value.MustOf([]string{`{"name": "api"}`, `{}`})}}
+
+	task := &dtos.Task{Type: "validate", Rule: "json_schema", SchemaFile: schemaFile}
+	assert.NoError(t, validator.Check(task, deps))
+	assert.Equal(t, value.MustOf([]bool{true, false}), task.Result)
+
+	// the compiled schema is reused, the file is not read again
+	assert.NoError(t, os.Remove(schemaFile))
+	task = &dtos.Task{Type: "validate", Rule: "json_schema", SchemaFile: schemaFile}
+	assert.NoError(t, validator.Check(task, deps))
+	assert.Equal(t, value.MustOf([]bool{true, false}), task.Result)
+
+	task = &dtos.Task{Type: "validate", Rule: "json_schema", SchemaFile: schemaFile}
+	assert.ErrorContains(t, validators.NewValidator(nil).Check(task, deps), "failed to read schema")
+}
+
+func TestValidator_CheckForEachResults(t *testing.T) {
+	dep := &dtos.Task{
+		ID:      "versions",
+		ForEach: &dtos.TaskForEach{Dependency: "services"},
+		Result:  value.MustOf(map[string]interface{}{"api": []string{"1.23.0"}, "worker": "1.21.4"}),
+	}
+	task := dtos.Task{Type: "validate", Rule: "semver", Pattern: ">= 1.22"}
+
+	assert.NoError(t, validators.NewValidator(nil).Check(&task, []*dtos.Task{dep}))
+	assert.Equal(t, value.MustOf(map[string]interface{}{"api": []bool{true}, "worker": false}), task.Result)
+
+	// Objects extracted by other facts are checked as a whole
+	dep.ForEach = nil
+	assert.ErrorContains(t, validators.NewValidator(nil).Check(&task, []*dtos.Task{dep}), "expected a string, got an object")
+}
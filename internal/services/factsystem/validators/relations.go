@@ -0,0 +1,105 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/registry"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+)
+
+// relationRule adapts a rule comparing the results of several dependencies to the registry.
+func relationRule(check func(task *dtos.Task, deps []*dtos.Task) (bool, error)) registry.ValidationRule {
+	return registry.ValidationRuleFunc(func(task *dtos.Task, deps []*dtos.Task) (value.Value, error) {
+		for _, dep := range deps {
+			if dep.Result.IsNull() {
+				return value.Null, errors.New("dependency result not provided")
+			}
+		}
+
+		ok, err := check(task, deps)
+		if err != nil {
+			return value.Null, err
+		}
+
+		return value.Bool(ok), nil
+	})
+}
+
+// validateDependeciesMatch checks that all the dependencies returned the same result.
+// Lists are compared item by item, or regardless of order and duplicates with the set match mode.
+func (fc *Validator) validateDependeciesMatch(task *dtos.Task, deps []*dtos.Task) (bool, error) {
+	if len(deps) < 2 {
+		return false, fmt.Errorf("rule %s expects at least 2 dependencies, got %d", task.Rule, len(deps))
+	}
+
+	var compare func(a, b value.Value) bool
+	switch dtos.TaskMatchMode(task.MatchMode) {
+	case "", dtos.OrderedMatchMode:
+		compare = value.Value.Equal
+	case dtos.SetMatchMode:
+		compare = func(a, b value.Value) bool {
+			setA, setB := toSet(a), toSet(b)
+			return len(setA) == len(setB) && isSubset(setA, setB)
+		}
+	default:
+		return false, fmt.Errorf("unknown match mode %s", task.MatchMode)
+	}
+
+	for _, dep := range deps[1:] {
+		if !compare(deps[0].Result, dep.Result) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// validateSubsetOf checks that every item returned by the first dependency is returned by the second one.
+func (fc *Validator) validateSubsetOf(task *dtos.Task, deps []*dtos.Task) (bool, error) {
+	if len(deps) != 2 {
+		return false, fmt.Errorf("rule %s expects 2 dependencies, got %d", task.Rule, len(deps))
+	}
+
+	return isSubset(toSet(deps[0].Result), toSet(deps[1].Result)), nil
+}
+
+// validateIntersects checks that at least one item is returned by all the dependencies.
+func (fc *Validator) validateIntersects(task *dtos.Task, deps []*dtos.Task) (bool, error) {
+	if len(deps) < 2 {
+		return false, fmt.Errorf("rule %s expects at least 2 dependencies, got %d", task.Rule, len(deps))
+	}
+
+	common := toSet(deps[0].Result)
+	for _, dep := range deps[1:] {
+		set := toSet(dep.Result)
+		for key := range common {
+			if !set[key] {
+				delete(common, key)
+			}
+		}
+	}
+
+	return len(common) > 0, nil
+}
+
+// toSet returns the distinct items of a list, a single value is a set of one item.
+// Items are keyed by their JSON encoding so that lists and objects can be members too.
+func toSet(v value.Value) map[string]bool {
+	items := v.Items()
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item.Key()] = true
+	}
+	return set
+}
+
+func isSubset(subset, set map[string]bool) bool {
+	for key := range subset {
+		if !set[key] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package validators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/motain/of-catalog/internal/services/factsystem/dtos"
+	"github.com/motain/of-catalog/internal/services/factsystem/utils"
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/prometheus/common/model"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// validateSemver checks a version against the constraint defined in the task pattern, e.g. ">= 1.22".
+// Prefixes such as "v" or "go" are ignored and partial versions are completed with zeros.
+func (fc *Validator) validateSemver(task *dtos.Task, value string) (bool, error) {
+	constraint, constraintErr := semver.NewConstraint(task.Pattern)
+	if constraintErr != nil {
+		return false, fmt.Errorf("invalid semver constraint %q: %v", task.Pattern, constraintErr)
+	}
+
+	trimmed := strings.TrimLeftFunc(strings.TrimSpace(value), func(r rune) bool { return r < '0' || r > '9' })
+	version, versionErr := semver.NewVersion(trimmed)
+	if versionErr != nil {
+		return false, fmt.Errorf("invalid version %q: %v", value, versionErr)
+	}
+
+	return constraint.Check(version), nil
+}
+
+// validateJSONSchema checks a document against the schema of the task.
+// Documents can be JSON or YAML content, or values extracted with jsonPath.
+func (fc *Validator) validateJSONSchema(task *dtos.Task, v value.Value) (bool, error) {
+	schema, schemaErr := fc.compileSchema(task)
+	if schemaErr != nil {
+		return false, schemaErr
+	}
+
+	content, isContent := v.Interface().(string)
+	if !isContent {
+		encoded, marshalErr := json.Marshal(v)
+		if marshalErr != nil {
+			return false, fmt.Errorf("invalid document: %v", marshalErr)
+		}
+		content = string(encoded)
+	}
+
+	document, documentErr := utils.ParseDocument([]byte(content))
+	if documentErr != nil {
+		return false, fmt.Errorf("invalid document: %v", documentErr)
+	}
+
+	return schema.Validate(document) == nil, nil
+}
+
+// compileSchema returns the compiled schema of the task. Schemas are cached by absolute file path or
+// by content for inline schemas, so that list items and forEach results share the compiled schema.
+func (fc *Validator) compileSchema(task *dtos.Task) (*jsonschema.Schema, error) {
+	key, keyErr := schemaKey(task)
+	if keyErr != nil {
+		return nil, keyErr
+	}
+
+	fc.schemasMu.Lock()
+	defer fc.schemasMu.Unlock()
+
+	if schema, found := fc.schemas[key]; found {
+		return schema, nil
+	}
+
+	schema, compileErr := utils.CompileSchema(task.Schema, task.SchemaFile)
+	if compileErr != nil {
+		return nil, compileErr
+	}
+	fc.schemas[key] = schema
+	return schema, nil
+}
+
+func schemaKey(task *dtos.Task) (string, error) {
+	if task.SchemaFile != "" && task.Schema == nil {
+		absolute, absErr := filepath.Abs(task.SchemaFile)
+		if absErr != nil {
+			return "", absErr
+		}
+		return "file:" + absolute, nil
+	}
+
+	// inline schemas, map keys are sorted by json.Marshal
+	encoded, marshalErr := json.Marshal(task.Schema)
+	if marshalErr != nil {
+		return "", fmt.Errorf("invalid schema: %v", marshalErr)
+	}
+	sum := sha256.Sum256(encoded)
+	return "inline:" + hex.EncodeToString(sum[:]), nil
+}
+
+// validateAge checks that a timestamp is not older than the max age of the task.
+// Timestamps can be RFC3339, dates (2006-01-02) or unix timestamps in seconds.
+func (fc *Validator) validateAge(task *dtos.Task, value string) (bool, error) {
+	maxAge, maxAgeErr := model.ParseDuration(task.MaxAge)
+	if maxAgeErr != nil {
+		return false, fmt.Errorf("invalid max age %q: %v", task.MaxAge, maxAgeErr)
+	}
+
+	timestamp, timestampErr := parseTimestamp(value)
+	if timestampErr != nil {
+		return false, timestampErr
+	}
+
+	return time.Since(timestamp) <= time.Duration(maxAge), nil
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", time.DateOnly} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(seconds * 1000)), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
@@ -0,0 +1,359 @@
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a fact result.
+type Kind int
+
+const (
+	NullKind Kind = iota
+	BoolKind
+	NumberKind
+	StringKind
+	ListKind
+	ObjectKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case BoolKind:
+		return "boolean"
+	case NumberKind:
+		return "number"
+	case StringKind:
+		return "string"
+	case ListKind:
+		return "list"
+	case ObjectKind:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+// Value is the result of a fact: null, a boolean, a number, a string, a list or an object, as in JSON.
+// The zero value is null, the result of facts that did not run or did not extract anything.
+//
+// Accessors such as Bool only accept values of their kind, conversions such as ToNumber accept
+// the kinds that have an obvious conversion and fail with an error naming the kind otherwise.
+type Value struct {
+	kind   Kind
+	b      bool
+	n      float64
+	s      string
+	items  []Value
+	fields map[string]Value
+}
+
+// Null is the result of facts without result.
+var Null = Value{}
+
+func Bool(b bool) Value {
+	return Value{kind: BoolKind, b: b}
+}
+
+func Number(n float64) Value {
+	return Value{kind: NumberKind, n: n}
+}
+
+func String(s string) Value {
+	return Value{kind: StringKind, s: s}
+}
+
+func List(items ...Value) Value {
+	if items == nil {
+		items = []Value{}
+	}
+	return Value{kind: ListKind, items: items}
+}
+
+func Object(fields map[string]Value) Value {
+	if fields == nil {
+		fields = map[string]Value{}
+	}
+	return Value{kind: ObjectKind, fields: fields}
+}
+
+// Of converts a Go value to a Value: numbers of any type become numbers, slices and arrays lists,
+// maps objects and raw data ([]byte) strings.
+func Of(v interface{}) (Value, error) {
+	switch t := v.(type) {
+	case nil:
+		return Null, nil
+	case Value:
+		return t, nil
+	case bool:
+		return Bool(t), nil
+	case string:
+		return String(t), nil
+	case []byte:
+		if t == nil {
+			return Null, nil
+		}
+		return String(string(t)), nil
+	case json.RawMessage:
+		return String(string(t)), nil
+	case json.Number:
+		n, err := t.Float64()
+		if err != nil {
+			return Null, fmt.Errorf("invalid number %q", t)
+		}
+		return Number(n), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Number(float64(rv.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Number(float64(rv.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return Number(rv.Float()), nil
+	case reflect.Slice, reflect.Array:
+		items := make([]Value, rv.Len())
+		for i := range items {
+			item, err := Of(rv.Index(i).Interface())
+			if err != nil {
+				return Null, err
+			}
+			items[i] = item
+		}
+		return List(items...), nil
+	case reflect.Map:
+		fields := make(map[string]Value, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			field, err := Of(iter.Value().Interface())
+			if err != nil {
+				return Null, err
+			}
+			fields[fmt.Sprintf("%v", iter.Key().Interface())] = field
+		}
+		return Object(fields), nil
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return Null, nil
+		}
+		return Of(rv.Elem().Interface())
+	default:
+		return Null, fmt.Errorf("unsupported result type %T", v)
+	}
+}
+
+// MustOf is like Of but panics on unsupported types, it is meant for values known to be valid.
+func MustOf(v interface{}) Value {
+	result, err := Of(v)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+func (v Value) IsNull() bool {
+	return v.kind == NullKind
+}
+
+func (v Value) Bool() (bool, error) {
+	if v.kind != BoolKind {
+		return false, v.kindError(BoolKind)
+	}
+	return v.b, nil
+}
+
+func (v Value) Number() (float64, error) {
+	if v.kind != NumberKind {
+		return 0, v.kindError(NumberKind)
+	}
+	return v.n, nil
+}
+
+func (v Value) String() string {
+	text, err := v.ToString()
+	if err != nil {
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+	return text
+}
+
+func (v Value) List() ([]Value, error) {
+	if v.kind != ListKind {
+		return nil, v.kindError(ListKind)
+	}
+	return v.items, nil
+}
+
+func (v Value) Object() (map[string]Value, error) {
+	if v.kind != ObjectKind {
+		return nil, v.kindError(ObjectKind)
+	}
+	return v.fields, nil
+}
+
+// Items returns the items of a list, or the value itself as a single item. Null has no items.
+func (v Value) Items() []Value {
+	switch v.kind {
+	case NullKind:
+		return nil
+	case ListKind:
+		return v.items
+	default:
+		return []Value{v}
+	}
+}
+
+// ToNumber converts numbers, booleans (1 and 0) and numeric strings to a number.
+func (v Value) ToNumber() (float64, error) {
+	switch v.kind {
+	case NumberKind:
+		return v.n, nil
+	case BoolKind:
+		if v.b {
+			return 1, nil
+		}
+		return 0, nil
+	case StringKind:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v.s), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", v.s)
+		}
+		return parsed, nil
+	case ListKind:
+		return 0, fmt.Errorf("expected a number, got a list of %d items", len(v.items))
+	default:
+		return 0, v.kindError(NumberKind)
+	}
+}
+
+// ToString converts scalars to a string, numbers use the shortest representation (e.g. 2 or 0.5).
+func (v Value) ToString() (string, error) {
+	switch v.kind {
+	case StringKind:
+		return v.s, nil
+	case NumberKind:
+		return strconv.FormatFloat(v.n, 'f', -1, 64), nil
+	case BoolKind:
+		return strconv.FormatBool(v.b), nil
+	default:
+		return "", v.kindError(StringKind)
+	}
+}
+
+// Interface returns the value as decoded by encoding/json: nil, bool, float64, string, []interface{}
+// or map[string]interface{}.
+func (v Value) Interface() interface{} {
+	switch v.kind {
+	case BoolKind:
+		return v.b
+	case NumberKind:
+		return v.n
+	case StringKind:
+		return v.s
+	case ListKind:
+		items := make([]interface{}, len(v.items))
+		for i, item := range v.items {
+			items[i] = item.Interface()
+		}
+		return items
+	case ObjectKind:
+		fields := make(map[string]interface{}, len(v.fields))
+		for key, field := range v.fields {
+			fields[key] = field.Interface()
+		}
+		return fields
+	default:
+		return nil
+	}
+}
+
+// Equal reports whether both values have the same kind and content, lists are compared item by item.
+func (v Value) Equal(other Value) bool {
+	if v.kind != other.kind {
+		return false
+	}
+
+	switch v.kind {
+	case ListKind:
+		if len(v.items) != len(other.items) {
+			return false
+		}
+		for i := range v.items {
+			if !v.items[i].Equal(other.items[i]) {
+				return false
+			}
+		}
+		return true
+	case ObjectKind:
+		if len(v.fields) != len(other.fields) {
+			return false
+		}
+		for key, field := range v.fields {
+			otherField, found := other.fields[key]
+			if !found || !field.Equal(otherField) {
+				return false
+			}
+		}
+		return true
+	default:
+		return v.b == other.b && v.n == other.n && v.s == other.s
+	}
+}
+
+// Key returns a string identifying the value, equal values have the same key.
+func (v Value) Key() string {
+	encoded, _ := json.Marshal(v)
+	return string(encoded)
+}
+
+// MarshalJSON encodes the value as JSON, object keys are sorted.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.Interface())
+}
+
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	parsed, err := Of(decoded)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+func (v Value) kindError(expected Kind) error {
+	return fmt.Errorf("expected %s, got %s", article(expected), v.describe())
+}
+
+func (v Value) describe() string {
+	switch v.kind {
+	case NullKind:
+		return "null"
+	case StringKind:
+		return fmt.Sprintf("string %q", v.s)
+	case ListKind, ObjectKind:
+		return article(v.kind)
+	default:
+		return fmt.Sprintf("%s %s", v.kind, v.String())
+	}
+}
+
+func article(kind Kind) string {
+	if kind == ObjectKind {
+		return "an object"
+	}
+	return "a " + kind.String()
+}
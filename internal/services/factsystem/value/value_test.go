@@ -0,0 +1,115 @@
+package value_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/factsystem/value"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       interface{}
+		expected    value.Value
+		expectedErr string
+	}{
+		{name: "nil", input: nil, expected: value.Null},
+		{name: "int", input: 3, expected: value.Number(3)},
+		{name: "json number", input: json.Number("2.5"), expected: value.Number(2.5)},
+		{name: "raw data", input: []byte(`{"a": 1}`), expected: value.String(`{"a": 1}`)},
+		{name: "no raw data", input: []byte(nil), expected: value.Null},
+		{name: "typed list", input: []bool{true, false}, expected: value.List(value.Bool(true), value.Bool(false))},
+		{
+			name:     "nested",
+			input:    map[string]interface{}{"tags": []string{"go"}, "replicas": 2},
+			expected: value.Object(map[string]value.Value{"tags": value.List(value.String("go")), "replicas": value.Number(2)}),
+		},
+		{name: "unsupported", input: make(chan int), expectedErr: "unsupported result type chan int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := value.Of(tt.input)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestValue_ToNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       value.Value
+		expected    float64
+		expectedErr string
+	}{
+		{name: "number", input: value.Number(42.5), expected: 42.5},
+		{name: "true", input: value.Bool(true), expected: 1},
+		{name: "false", input: value.Bool(false), expected: 0},
+		{name: "numeric string", input: value.String(" 123.45\n"), expected: 123.45},
+		{name: "invalid string", input: value.String("not_a_number"), expectedErr: `invalid number "not_a_number"`},
+		{name: "list", input: value.MustOf([]int{1, 2, 3}), expectedErr: "expected a number, got a list of 3 items"},
+		{name: "object", input: value.MustOf(map[string]int{"a": 1}), expectedErr: "expected a number, got an object"},
+		{name: "null", input: value.Null, expectedErr: "expected a number, got null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.input.ToNumber()
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestValue_Accessors(t *testing.T) {
+	_, err := value.String("true").Bool()
+	assert.EqualError(t, err, `expected a boolean, got string "true"`)
+
+	_, err = value.Number(2).List()
+	assert.EqualError(t, err, "expected a list, got number 2")
+
+	text, err := value.Number(0.5).ToString()
+	assert.NoError(t, err)
+	assert.Equal(t, "0.5", text)
+
+	_, err = value.List().ToString()
+	assert.EqualError(t, err, "expected a string, got a list")
+
+	assert.Equal(t, []value.Value{value.Number(1)}, value.Number(1).Items())
+	assert.Empty(t, value.Null.Items())
+}
+
+func TestValue_Equal(t *testing.T) {
+	assert.True(t, value.MustOf([]interface{}{1, "a"}).Equal(value.MustOf([]interface{}{1.0, "a"})))
+	assert.True(t, value.MustOf(map[string]int{"a": 1}).Equal(value.MustOf(map[string]float64{"a": 1})))
+	assert.False(t, value.MustOf([]int{1, 2}).Equal(value.MustOf([]int{2, 1})))
+	assert.False(t, value.Number(1).Equal(value.Bool(true)))
+	assert.False(t, value.String("1").Equal(value.Number(1)))
+}
+
+func TestValue_JSON(t *testing.T) {
+	var decoded value.Value
+	assert.NoError(t, json.Unmarshal([]byte(`{"b": [1, true, null], "a": "x"}`), &decoded))
+	assert.Equal(t, value.Object(map[string]value.Value{
+		"a": value.String("x"),
+		"b": value.List(value.Number(1), value.Bool(true), value.Null),
+	}), decoded)
+
+	encoded, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":"x","b":[1,true,null]}`, string(encoded))
+	assert.Equal(t, string(encoded), decoded.Key())
+}
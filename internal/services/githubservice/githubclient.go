@@ -6,6 +6,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/google/go-github/v58/github"
 	"github.com/motain/of-catalog/internal/services/configservice"
@@ -19,43 +22,71 @@ type GitHubRepositoriesInterface interface {
 }
 
 type GitHubClientInterface interface {
-	GetRepo() GitHubRepositoriesInterface
+	GetRepo() (GitHubRepositoriesInterface, error)
 	SearchCode(repo, query string) ([]string, error)
+	DownloadTarball(repo, ref string) (io.ReadCloser, error)
 }
 
+// GitHubClient is authenticated lazily on first use, so that commands working only with
+// local sources do not require a GitHub token.
 type GitHubClient struct {
-	client *github.Client
+	cfg configservice.ConfigServiceInterface
+	kr  keyringservice.KeyringServiceInterface
+
+	once      sync.Once
+	client    *github.Client
+	clientErr error
 }
 
 func NewGitHubClient(
 	cfg configservice.ConfigServiceInterface,
 	kr keyringservice.KeyringServiceInterface,
 ) GitHubClientInterface {
-	serviceName := "gh:github.com"
-
-	token := cfg.GetGithubToken()
-	if token == "" {
-		var tokenErr error
-		token, tokenErr = kr.Get(serviceName, cfg.GetGithubUser())
-		if tokenErr != nil {
-			panic(tokenErr)
+	return &GitHubClient{cfg: cfg, kr: kr}
+}
+
+// gh returns the authenticated client, looking up the token on the first call.
+// A failed lookup is returned by every call.
+func (gh *GitHubClient) gh() (*github.Client, error) {
+	gh.once.Do(func() {
+		serviceName := "gh:github.com"
+
+		token := gh.cfg.GetGithubToken()
+		if token == "" {
+			var tokenErr error
+			token, tokenErr = gh.kr.Get(serviceName, gh.cfg.GetGithubUser())
+			if tokenErr != nil {
+				gh.clientErr = fmt.Errorf("failed to get the GitHub token: %w", tokenErr)
+				return
+			}
 		}
-	}
 
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
+		ctx := context.Background()
+		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+		tc := oauth2.NewClient(ctx, ts)
+
+		gh.client = github.NewClient(tc)
+	})
 
-	return &GitHubClient{client: github.NewClient(tc)}
+	return gh.client, gh.clientErr
 }
 
-func (gh *GitHubClient) GetRepo() GitHubRepositoriesInterface {
-	return gh.client.Repositories
+func (gh *GitHubClient) GetRepo() (GitHubRepositoriesInterface, error) {
+	client, clientErr := gh.gh()
+	if clientErr != nil {
+		return nil, clientErr
+	}
+	return client.Repositories, nil
 }
 
 func (gh *GitHubClient) SearchCode(repo, query string) ([]string, error) {
+	client, clientErr := gh.gh()
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
 	q := fmt.Sprintf("repo:%s %s", repo, query)
-	codeResult, res, searchErr := gh.client.Search.Code(context.Background(), q, nil)
+	codeResult, res, searchErr := client.Search.Code(context.Background(), q, nil)
 	if searchErr != nil {
 		return nil, searchErr
 	}
@@ -77,3 +108,41 @@ func (gh *GitHubClient) SearchCode(repo, query string) ([]string, error) {
 
 	return result, nil
 }
+
+// DownloadTarball streams the gzipped tarball of the given repository (owner/name) at ref.
+// An empty ref resolves to the default branch.
+func (gh *GitHubClient) DownloadTarball(repo, ref string) (io.ReadCloser, error) {
+	owner, name, found := strings.Cut(repo, "/")
+	if !found {
+		return nil, fmt.Errorf("invalid repository %s, expected owner/name", repo)
+	}
+
+	client, clientErr := gh.gh()
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	ctx := context.Background()
+	opts := &github.RepositoryContentGetOptions{Ref: ref}
+	archiveURL, _, linkErr := client.Repositories.GetArchiveLink(ctx, owner, name, github.Tarball, opts, 1)
+	if linkErr != nil {
+		return nil, fmt.Errorf("failed to get archive link: %w", linkErr)
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, archiveURL.String(), nil)
+	if reqErr != nil {
+		return nil, fmt.Errorf("failed to create archive request: %w", reqErr)
+	}
+
+	res, downloadErr := client.Client().Do(req)
+	if downloadErr != nil {
+		return nil, fmt.Errorf("failed to download archive: %w", downloadErr)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, fmt.Errorf("failed to download archive: %d", res.StatusCode)
+	}
+
+	return res.Body, nil
+}
@@ -0,0 +1,42 @@
+package githubservice_test
+
+import (
+	"errors"
+	"testing"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/githubservice"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type failingKeyring struct{ calls int }
+
+func (k *failingKeyring) Set(service, user, secret string) error { return nil }
+func (k *failingKeyring) Delete(service, user string) error      { return nil }
+func (k *failingKeyring) Get(service, user string) (string, error) {
+	k.calls++
+	return "", errors.New("secret not found in keyring")
+}
+
+func TestGitHubClient_MissingToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	mockConfig.EXPECT().GetGithubToken().Return("")
+	mockConfig.EXPECT().GetGithubUser().Return("octocat")
+	keyring := &failingKeyring{}
+
+	client := githubservice.NewGitHubClient(mockConfig, keyring)
+
+	_, err := client.GetRepo()
+	assert.EqualError(t, err, "failed to get the GitHub token: secret not found in keyring")
+
+	// the lookup is not retried and the error is reported by every call
+	_, err = client.SearchCode("motain/of-catalog", "TODO")
+	assert.EqualError(t, err, "failed to get the GitHub token: secret not found in keyring")
+	_, err = client.DownloadTarball("motain/of-catalog", "main")
+	assert.EqualError(t, err, "failed to get the GitHub token: secret not found in keyring")
+	assert.Equal(t, 1, keyring.calls)
+}
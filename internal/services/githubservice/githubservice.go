@@ -5,6 +5,7 @@ package githubservice
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/google/go-github/v58/github"
@@ -17,6 +18,7 @@ type GitHubServiceInterface interface {
 	GetFileContent(repo, path string) (string, error)
 	GetRepoProperties(repo string) (map[string]string, error)
 	Search(repo, query string) ([]string, error)
+	DownloadTarball(repo, ref string) (io.ReadCloser, error)
 }
 
 type GitHubService struct {
@@ -35,7 +37,12 @@ func (gh *GitHubService) GetRepoURL(repo string) string {
 // Get repository details
 func (gh *GitHubService) GetRepo(repo string) (*github.Repository, error) {
 	ctx := context.Background()
-	repository, _, err := gh.client.GetRepo().Get(ctx, gh.owner, repo)
+	repositories, clientErr := gh.client.GetRepo()
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	repository, _, err := repositories.Get(ctx, gh.owner, repo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch repo: %w", err)
 	}
@@ -44,7 +51,12 @@ func (gh *GitHubService) GetRepo(repo string) (*github.Repository, error) {
 
 func (gh *GitHubService) GetFileExists(repo, path string) (bool, error) {
 	ctx := context.Background()
-	fileContent, _, _, err := gh.client.GetRepo().GetContents(ctx, gh.owner, repo, path, nil)
+	repositories, clientErr := gh.client.GetRepo()
+	if clientErr != nil {
+		return false, clientErr
+	}
+
+	fileContent, _, _, err := repositories.GetContents(ctx, gh.owner, repo, path, nil)
 	if err != nil {
 		if _, ok := err.(*github.ErrorResponse); ok && err.(*github.ErrorResponse).Response.StatusCode == 404 {
 			return false, nil
@@ -58,7 +70,12 @@ func (gh *GitHubService) GetFileExists(repo, path string) (bool, error) {
 // Get file contents
 func (gh *GitHubService) GetFileContent(repo, path string) (string, error) {
 	ctx := context.Background()
-	fileContent, _, _, fetchErr := gh.client.GetRepo().GetContents(ctx, gh.owner, repo, path, nil)
+	repositories, clientErr := gh.client.GetRepo()
+	if clientErr != nil {
+		return "", clientErr
+	}
+
+	fileContent, _, _, fetchErr := repositories.GetContents(ctx, gh.owner, repo, path, nil)
 	if fetchErr != nil {
 		return "", fmt.Errorf("failed to fetch file: %w", fetchErr)
 	}
@@ -74,7 +91,12 @@ func (gh *GitHubService) GetFileContent(repo, path string) (string, error) {
 func (gh *GitHubService) GetRepoProperties(repo string) (map[string]string, error) {
 	ctx := context.Background()
 
-	repoDetails, _, err := gh.client.GetRepo().Get(ctx, gh.owner, repo)
+	repositories, clientErr := gh.client.GetRepo()
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	repoDetails, _, err := repositories.Get(ctx, gh.owner, repo)
 	if err != nil {
 		return nil, err
 	}
@@ -99,3 +121,8 @@ func (gh *GitHubService) Search(repo, query string) ([]string, error) {
 	repoWithOwner := fmt.Sprintf("%s/%s", gh.owner, repo)
 	return gh.client.SearchCode(repoWithOwner, query)
 }
+
+func (gh *GitHubService) DownloadTarball(repo, ref string) (io.ReadCloser, error) {
+	repoWithOwner := fmt.Sprintf("%s/%s", gh.owner, repo)
+	return gh.client.DownloadTarball(repoWithOwner, ref)
+}
@@ -10,6 +10,7 @@
 package githubservice
 
 import (
+	io "io"
 	reflect "reflect"
 
 	github "github.com/google/go-github/v58/github"
@@ -40,6 +41,21 @@ func (m *MockGitHubServiceInterface) EXPECT() *MockGitHubServiceInterfaceMockRec
 	return m.recorder
 }
 
+// DownloadTarball mocks base method.
+func (m *MockGitHubServiceInterface) DownloadTarball(repo, ref string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadTarball", repo, ref)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadTarball indicates an expected call of DownloadTarball.
+func (mr *MockGitHubServiceInterfaceMockRecorder) DownloadTarball(repo, ref any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadTarball", reflect.TypeOf((*MockGitHubServiceInterface)(nil).DownloadTarball), repo, ref)
+}
+
 // GetFileContent mocks base method.
 func (m *MockGitHubServiceInterface) GetFileContent(repo, path string) (string, error) {
 	m.ctrl.T.Helper()
@@ -30,8 +30,13 @@ func NewJSONService(config configservice.ConfigServiceInterface) JSONServiceInte
 }
 
 func (c *JSONTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
+	// Defaults only, facts can override them with their own headers
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	return c.Transport.RoundTrip(req)
 }
@@ -0,0 +1,173 @@
+package prometheusservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/motain/of-catalog/internal/services/configservice"
+	"github.com/motain/of-catalog/internal/utils/awsutils"
+)
+
+// DefaultDatasource is the name of the datasource used by facts not declaring one.
+const DefaultDatasource = "default"
+
+// AuthMode defines how requests to a Prometheus datasource are authenticated.
+type AuthMode string
+
+const (
+	// NoAuth sends requests without credentials (plain Prometheus, local instances).
+	NoAuth AuthMode = "none"
+	// BasicAuth sends a username and password (e.g. Mimir or VictoriaMetrics behind a proxy).
+	BasicAuth AuthMode = "basic"
+	// BearerAuth sends a bearer token in the Authorization header.
+	BearerAuth AuthMode = "bearer"
+	// SigV4Auth signs requests with AWS credentials (AWS Managed Prometheus).
+	SigV4Auth AuthMode = "sigv4"
+)
+
+// Datasource describes a Prometheus compatible endpoint and how to authenticate against it.
+//
+// Datasources are configured through environment variables. The default datasource reads
+// PROMETHEUS_URL, PROMETHEUS_AUTH, PROMETHEUS_USERNAME, PROMETHEUS_PASSWORD, PROMETHEUS_TOKEN and
+// PROMETHEUS_ORG_ID, a datasource named "my-mimir" reads the same variables prefixed with
+// PROMETHEUS_MY_MIMIR_ (e.g. PROMETHEUS_MY_MIMIR_URL).
+//
+// SigV4 datasources use AWS_REGION and AWS_ROLE unless <prefix>_REGION and <prefix>_ROLE are set.
+type Datasource struct {
+	Name     string
+	URL      string
+	Auth     AuthMode
+	Username string
+	Password string
+	Token    string
+	OrgID    string // Tenant sent as X-Scope-OrgID for multi-tenant backends like Mimir, Cortex or Thanos
+	Region   string
+	Role     string
+}
+
+// LoadDatasource reads the configuration of the named datasource.
+// An empty name resolves to DefaultDatasource.
+//
+// Parameters:
+//   - cfg: Configuration service providing the environment variables
+//   - name: The name of the datasource
+//
+// Returns:
+//   - Datasource: The datasource configuration
+//   - error: Returned when the URL is missing or the auth mode is unknown
+func LoadDatasource(cfg configservice.ConfigServiceInterface, name string) (Datasource, error) {
+	if name == "" {
+		name = DefaultDatasource
+	}
+
+	prefix := datasourceEnvPrefix(name)
+	get := func(suffix string) string { return cfg.Get(prefix + "_" + suffix) }
+
+	ds := Datasource{
+		Name:     name,
+		URL:      get("URL"),
+		Auth:     AuthMode(strings.ToLower(get("AUTH"))),
+		Username: get("USERNAME"),
+		Password: get("PASSWORD"),
+		Token:    get("TOKEN"),
+		OrgID:    get("ORG_ID"),
+		Region:   get("REGION"),
+		Role:     get("ROLE"),
+	}
+
+	if name == DefaultDatasource {
+		ds.URL = cfg.GetPrometheusURL()
+		// Before datasources existed the only supported backend was AWS Managed Prometheus
+		if ds.Auth == "" {
+			ds.Auth = SigV4Auth
+		}
+	}
+
+	if ds.Auth == "" {
+		ds.Auth = NoAuth
+	}
+	if ds.Region == "" {
+		ds.Region = cfg.GetAWSRegion()
+	}
+	if ds.Role == "" {
+		ds.Role = cfg.GetAWSRole()
+	}
+
+	if ds.URL == "" {
+		return Datasource{}, fmt.Errorf("prometheus datasource %s: %s_URL not configured", name, prefix)
+	}
+
+	switch ds.Auth {
+	case NoAuth, BasicAuth, BearerAuth, SigV4Auth:
+		return ds, nil
+	default:
+		return Datasource{}, fmt.Errorf("prometheus datasource %s: unknown auth mode %s", name, ds.Auth)
+	}
+}
+
+// roundTripper builds the authenticated transport for the datasource.
+func (ds Datasource) roundTripper(ctx context.Context) (http.RoundTripper, error) {
+	var transport http.RoundTripper = http.DefaultTransport
+
+	switch ds.Auth {
+	case BasicAuth:
+		transport = &basicAuthRoundTripper{Transport: transport, Username: ds.Username, Password: ds.Password}
+	case BearerAuth:
+		transport = &headerRoundTripper{Transport: transport, Name: "Authorization", Value: "Bearer " + ds.Token}
+	case SigV4Auth:
+		awsCfg, err := awsutils.LoadConfig(ctx, ds.Region, ds.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		transport = &awsutils.SigV4RoundTripper{
+			Transport:   transport,
+			Region:      ds.Region,
+			Service:     "aps",
+			Credentials: awsCfg.Credentials,
+		}
+	}
+
+	if ds.OrgID != "" {
+		transport = &headerRoundTripper{Transport: transport, Name: "X-Scope-OrgID", Value: ds.OrgID}
+	}
+
+	return transport, nil
+}
+
+func datasourceEnvPrefix(name string) string {
+	if name == DefaultDatasource {
+		return "PROMETHEUS"
+	}
+
+	normalized := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name))
+	return "PROMETHEUS_" + normalized
+}
+
+// basicAuthRoundTripper adds HTTP basic authentication to every request.
+type basicAuthRoundTripper struct {
+	Transport http.RoundTripper
+	Username  string
+	Password  string
+}
+
+func (rt *basicAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqClone := req.Clone(req.Context())
+	reqClone.SetBasicAuth(rt.Username, rt.Password)
+	return rt.Transport.RoundTrip(reqClone)
+}
+
+// headerRoundTripper sets a static header on every request.
+type headerRoundTripper struct {
+	Transport http.RoundTripper
+	Name      string
+	Value     string
+}
+
+func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqClone := req.Clone(req.Context())
+	reqClone.Header.Set(rt.Name, rt.Value)
+	return rt.Transport.RoundTrip(reqClone)
+}
@@ -0,0 +1,200 @@
+package prometheusservice_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	configservice "github.com/motain/of-catalog/internal/services/configservice/mocks"
+	"github.com/motain/of-catalog/internal/services/prometheusservice"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const vectorResponse = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"api"},"value":[1700000000,"42"]}]}}`
+
+func newMockConfig(ctrl *gomock.Controller, env map[string]string) *configservice.MockConfigServiceInterface {
+	mockConfig := configservice.NewMockConfigServiceInterface(ctrl)
+	mockConfig.EXPECT().Get(gomock.Any()).DoAndReturn(func(key string) string { return env[key] }).AnyTimes()
+	mockConfig.EXPECT().GetPrometheusURL().Return(env["PROMETHEUS_URL"]).AnyTimes()
+	mockConfig.EXPECT().GetAWSRegion().Return("eu-west-1").AnyTimes()
+	mockConfig.EXPECT().GetAWSRole().Return("").AnyTimes()
+	return mockConfig
+}
+
+func TestLoadDatasource(t *testing.T) {
+	tests := []struct {
+		name        string
+		datasource  string
+		env         map[string]string
+		expected    prometheusservice.Datasource
+		expectedErr string
+	}{
+		{
+			name:       "default datasource keeps sigv4 for backward compatibility",
+			datasource: "",
+			env:        map[string]string{"PROMETHEUS_URL": "https://aps.example.com"},
+			expected: prometheusservice.Datasource{
+				Name:   "default",
+				URL:    "https://aps.example.com",
+				Auth:   prometheusservice.SigV4Auth,
+				Region: "eu-west-1",
+			},
+		},
+		{
+			name:       "named datasource reads prefixed variables",
+			datasource: "my-mimir",
+			env: map[string]string{
+				"PROMETHEUS_MY_MIMIR_URL":      "https://mimir.example.com/prometheus",
+				"PROMETHEUS_MY_MIMIR_AUTH":     "Basic",
+				"PROMETHEUS_MY_MIMIR_USERNAME": "user",
+				"PROMETHEUS_MY_MIMIR_PASSWORD": "secret",
+				"PROMETHEUS_MY_MIMIR_ORG_ID":   "tenant-1",
+			},
+			expected: prometheusservice.Datasource{
+				Name:     "my-mimir",
+				URL:      "https://mimir.example.com/prometheus",
+				Auth:     prometheusservice.BasicAuth,
+				Username: "user",
+				Password: "secret",
+				OrgID:    "tenant-1",
+				Region:   "eu-west-1",
+			},
+		},
+		{
+			name:       "named datasource defaults to no auth",
+			datasource: "local",
+			env:        map[string]string{"PROMETHEUS_LOCAL_URL": "http://localhost:9090"},
+			expected: prometheusservice.Datasource{
+				Name:   "local",
+				URL:    "http://localhost:9090",
+				Auth:   prometheusservice.NoAuth,
+				Region: "eu-west-1",
+			},
+		},
+		{
+			name:        "missing URL",
+			datasource:  "unknown",
+			env:         map[string]string{},
+			expectedErr: "prometheus datasource unknown: PROMETHEUS_UNKNOWN_URL not configured",
+		},
+		{
+			name:       "unknown auth mode",
+			datasource: "local",
+			env: map[string]string{
+				"PROMETHEUS_LOCAL_URL":  "http://localhost:9090",
+				"PROMETHEUS_LOCAL_AUTH": "kerberos",
+			},
+			expectedErr: "prometheus datasource local: unknown auth mode kerberos",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ds, err := prometheusservice.LoadDatasource(newMockConfig(ctrl, tt.env), tt.datasource)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ds)
+		})
+	}
+}
+
+func TestPrometheusClient_QueryAuth(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           func(url string) map[string]string
+		datasource    string
+		assertRequest func(t *testing.T, r *http.Request)
+	}{
+		{
+			name: "basic auth with tenant",
+			env: func(url string) map[string]string {
+				return map[string]string{
+					"PROMETHEUS_MIMIR_URL":      url,
+					"PROMETHEUS_MIMIR_AUTH":     "basic",
+					"PROMETHEUS_MIMIR_USERNAME": "user",
+					"PROMETHEUS_MIMIR_PASSWORD": "secret",
+					"PROMETHEUS_MIMIR_ORG_ID":   "tenant-1",
+				}
+			},
+			datasource: "mimir",
+			assertRequest: func(t *testing.T, r *http.Request) {
+				username, password, ok := r.BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "user", username)
+				assert.Equal(t, "secret", password)
+				assert.Equal(t, "tenant-1", r.Header.Get("X-Scope-OrgID"))
+			},
+		},
+		{
+			name: "bearer token",
+			env: func(url string) map[string]string {
+				return map[string]string{
+					"PROMETHEUS_THANOS_URL":   url,
+					"PROMETHEUS_THANOS_AUTH":  "bearer",
+					"PROMETHEUS_THANOS_TOKEN": "token-123",
+				}
+			},
+			datasource: "thanos",
+			assertRequest: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "Bearer token-123", r.Header.Get("Authorization"))
+				assert.Empty(t, r.Header.Get("X-Scope-OrgID"))
+			},
+		},
+		{
+			name: "default datasource without auth",
+			env: func(url string) map[string]string {
+				return map[string]string{"PROMETHEUS_URL": url, "PROMETHEUS_AUTH": "none"}
+			},
+			datasource: "",
+			assertRequest: func(t *testing.T, r *http.Request) {
+				assert.Empty(t, r.Header.Get("Authorization"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.assertRequest(t, r)
+				assert.Equal(t, "/api/v1/query", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(vectorResponse))
+			}))
+			defer server.Close()
+
+			client := prometheusservice.NewPrometheusClient(newMockConfig(ctrl, tt.env(server.URL)))
+
+			result, err := client.Query(tt.datasource, "up", time.Unix(1700000000, 0))
+			assert.NoError(t, err)
+
+			vector, ok := result.(model.Vector)
+			assert.True(t, ok)
+			assert.Len(t, vector, 1)
+			assert.Equal(t, model.SampleValue(42), vector[0].Value)
+		})
+	}
+}
+
+func TestPrometheusClient_QueryUnknownDatasource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Creating the client must not require any configuration
+	client := prometheusservice.NewPrometheusClient(newMockConfig(ctrl, map[string]string{}))
+
+	_, err := client.Query("missing", "up", time.Now())
+	assert.EqualError(t, err, "prometheus datasource missing: PROMETHEUS_MISSING_URL not configured")
+}
@@ -43,31 +43,31 @@ func (m *MockPrometheusClientInterface) EXPECT() *MockPrometheusClientInterfaceM
 }
 
 // Query mocks base method.
-func (m *MockPrometheusClientInterface) Query(query string, timestamp time.Time) (float64, error) {
+func (m *MockPrometheusClientInterface) Query(datasource, query string, timestamp time.Time) (model.Value, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Query", query, timestamp)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "Query", datasource, query, timestamp)
+	ret0, _ := ret[0].(model.Value)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Query indicates an expected call of Query.
-func (mr *MockPrometheusClientInterfaceMockRecorder) Query(query, timestamp any) *gomock.Call {
+func (mr *MockPrometheusClientInterfaceMockRecorder) Query(datasource, query, timestamp any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockPrometheusClientInterface)(nil).Query), query, timestamp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockPrometheusClientInterface)(nil).Query), datasource, query, timestamp)
 }
 
 // QueryRange mocks base method.
-func (m *MockPrometheusClientInterface) QueryRange(query string, r v1.Range) (model.Value, error) {
+func (m *MockPrometheusClientInterface) QueryRange(datasource, query string, r v1.Range) (model.Value, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryRange", query, r)
+	ret := m.ctrl.Call(m, "QueryRange", datasource, query, r)
 	ret0, _ := ret[0].(model.Value)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryRange indicates an expected call of QueryRange.
-func (mr *MockPrometheusClientInterfaceMockRecorder) QueryRange(query, r any) *gomock.Call {
+func (mr *MockPrometheusClientInterfaceMockRecorder) QueryRange(datasource, query, r any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRange", reflect.TypeOf((*MockPrometheusClientInterface)(nil).QueryRange), query, r)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRange", reflect.TypeOf((*MockPrometheusClientInterface)(nil).QueryRange), datasource, query, r)
 }
@@ -42,31 +42,31 @@ func (m *MockPrometheusServiceInterface) EXPECT() *MockPrometheusServiceInterfac
 }
 
 // InstantQuery mocks base method.
-func (m *MockPrometheusServiceInterface) InstantQuery(queryString string) (float64, error) {
+func (m *MockPrometheusServiceInterface) InstantQuery(datasource, queryString string) (model.Value, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InstantQuery", queryString)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "InstantQuery", datasource, queryString)
+	ret0, _ := ret[0].(model.Value)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InstantQuery indicates an expected call of InstantQuery.
-func (mr *MockPrometheusServiceInterfaceMockRecorder) InstantQuery(queryString any) *gomock.Call {
+func (mr *MockPrometheusServiceInterfaceMockRecorder) InstantQuery(datasource, queryString any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstantQuery", reflect.TypeOf((*MockPrometheusServiceInterface)(nil).InstantQuery), queryString)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InstantQuery", reflect.TypeOf((*MockPrometheusServiceInterface)(nil).InstantQuery), datasource, queryString)
 }
 
 // RangeQuery mocks base method.
-func (m *MockPrometheusServiceInterface) RangeQuery(queryString string, start, end time.Time, step time.Duration) (model.Value, error) {
+func (m *MockPrometheusServiceInterface) RangeQuery(datasource, queryString string, start, end time.Time, step time.Duration) (model.Value, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RangeQuery", queryString, start, end, step)
+	ret := m.ctrl.Call(m, "RangeQuery", datasource, queryString, start, end, step)
 	ret0, _ := ret[0].(model.Value)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RangeQuery indicates an expected call of RangeQuery.
-func (mr *MockPrometheusServiceInterfaceMockRecorder) RangeQuery(queryString, start, end, step any) *gomock.Call {
+func (mr *MockPrometheusServiceInterfaceMockRecorder) RangeQuery(datasource, queryString, start, end, step any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeQuery", reflect.TypeOf((*MockPrometheusServiceInterface)(nil).RangeQuery), queryString, start, end, step)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeQuery", reflect.TypeOf((*MockPrometheusServiceInterface)(nil).RangeQuery), datasource, queryString, start, end, step)
 }
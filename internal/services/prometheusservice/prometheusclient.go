@@ -5,127 +5,111 @@ package prometheusservice
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
-	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/prometheus/client_golang/api"
 	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 
 	"github.com/motain/of-catalog/internal/services/configservice"
-	"github.com/motain/of-catalog/internal/utils/awsutils"
 )
 
 // PrometheusClientInterface defines the contract for Prometheus client operations.
 // It provides methods for querying Prometheus data in different formats.
 type PrometheusClientInterface interface {
-	// Query executes an instant query at a specific timestamp
-	Query(query string, timestamp time.Time) (float64, error)
-	// QueryRange executes a query over a time range
-	QueryRange(query string, r v1.Range) (model.Value, error)
+	// Query executes an instant query at a specific timestamp against the named datasource
+	Query(datasource, query string, timestamp time.Time) (model.Value, error)
+	// QueryRange executes a query over a time range against the named datasource
+	QueryRange(datasource, query string, r v1.Range) (model.Value, error)
 }
 
-// PrometheusClient implements PrometheusClientInterface to interact with AWS Managed Prometheus.
-// It handles authentication and provides methods to query Prometheus data.
+// PrometheusClient implements PrometheusClientInterface to interact with Prometheus compatible datasources
+// (plain Prometheus, Thanos, Mimir, VictoriaMetrics or AWS Managed Prometheus).
+// Datasources are initialized lazily on first use, so commands that never query Prometheus
+// do not require any Prometheus or AWS configuration.
 type PrometheusClient struct {
-	api    v1.API // Underlying Prometheus API client
-	region string // AWS region for the Prometheus workspace
+	cfg configservice.ConfigServiceInterface
+
+	mu   sync.Mutex
+	apis map[string]v1.API // Prometheus API clients by datasource name
 }
 
-// NewPrometheusClient creates a new Prometheus client with AWS authentication.
-// It configures the client with either existing AWS credentials or assumes a specified role.
+// NewPrometheusClient creates a new Prometheus client.
+// No connection nor credential lookup happens until the first query.
 //
 // Parameters:
-//   - cfg: Configuration service providing AWS region, Prometheus URL, and role ARN
+//   - cfg: Configuration service providing the datasources configuration
 //
 // Returns:
-//   - PrometheusClientInterface: Configured Prometheus client
-//   - Panics if configuration is invalid or client creation fails
+//   - PrometheusClientInterface: Prometheus client
 func NewPrometheusClient(cfg configservice.ConfigServiceInterface) PrometheusClientInterface {
-	ctx := context.Background()
-	region := cfg.GetAWSRegion()
-	workspaceURL := cfg.GetPrometheusURL()
-
-	if workspaceURL == "" {
-		panic("Prometheus workspace URL not configured")
+	return &PrometheusClient{
+		cfg:  cfg,
+		apis: make(map[string]v1.API),
 	}
+}
 
-	// Initialize AWS configuration
-	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
-	if err != nil {
-		panic(fmt.Errorf("failed to load AWS config: %w", err))
+// api returns the API client of the named datasource, creating it on first use.
+func (pc *PrometheusClient) api(datasource string) (v1.API, error) {
+	if datasource == "" {
+		datasource = DefaultDatasource
 	}
 
-	// Set up credentials provider
-	credProvider := getCredentialsProvider(ctx, awsCfg, cfg.GetAWSRole())
-
-	// Create authenticated HTTP client
-	httpClient := &http.Client{
-		Transport: &awsutils.SigV4RoundTripper{
-			Transport:   http.DefaultTransport,
-			Region:      region,
-			Service:     "aps",
-			Credentials: credProvider,
-		},
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if promAPI, exists := pc.apis[datasource]; exists {
+		return promAPI, nil
 	}
 
-	// Initialize Prometheus client
-	promClient, err := api.NewClient(api.Config{
-		Address: workspaceURL,
-		Client:  httpClient,
-	})
-	if err != nil {
-		panic(fmt.Errorf("failed to create Prometheus client: %w", err))
+	ds, loadErr := LoadDatasource(pc.cfg, datasource)
+	if loadErr != nil {
+		return nil, loadErr
 	}
 
-	return &PrometheusClient{
-		api:    v1.NewAPI(promClient),
-		region: region,
+	transport, transportErr := ds.roundTripper(context.Background())
+	if transportErr != nil {
+		return nil, fmt.Errorf("prometheus datasource %s: %w", datasource, transportErr)
 	}
-}
 
-// getCredentialsProvider determines the appropriate AWS credentials provider.
-// It either uses existing credentials or assumes a specified role.
-func getCredentialsProvider(ctx context.Context, awsCfg aws.Config, roleARN string) aws.CredentialsProvider {
-	if roleARN == "" {
-		stsClient := sts.NewFromConfig(awsCfg)
-		_, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
-		if err != nil {
-			panic(fmt.Errorf("failed to get caller identity: %w", err))
-		}
-		return awsCfg.Credentials
+	promClient, clientErr := api.NewClient(api.Config{
+		Address:      ds.URL,
+		RoundTripper: transport,
+	})
+	if clientErr != nil {
+		return nil, fmt.Errorf("failed to create Prometheus client for datasource %s: %w", datasource, clientErr)
 	}
-	return stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsCfg), roleARN)
+
+	pc.apis[datasource] = v1.NewAPI(promClient)
+	return pc.apis[datasource], nil
 }
 
 // Query executes an instant query against Prometheus at the specified timestamp.
 // Returns the query result as a Prometheus model.Value.
-func (pc *PrometheusClient) Query(query string, timestamp time.Time) (float64, error) {
-	result, _, err := pc.api.Query(context.Background(), query, timestamp)
+func (pc *PrometheusClient) Query(datasource, query string, timestamp time.Time) (model.Value, error) {
+	promAPI, apiErr := pc.api(datasource)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	result, _, err := promAPI.Query(context.Background(), query, timestamp)
 	if err != nil {
 		fmt.Printf("Query: %s, Timestamp: %s\n", query, timestamp)
-		return 0, fmt.Errorf("failed to execute query: %w", err)
-	}
-	response := 0.0
-	if vector, ok := result.(model.Vector); ok {
-		for _, sample := range vector {
-			response = float64(sample.Value)
-		}
-	} else {
-		fmt.Println("Result is not a vector for query:", query)
+		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
-
-	return response, nil
+	return result, nil
 }
 
 // QueryRange executes a range query against Prometheus over the specified time range.
 // Returns the query result as a Prometheus model.Value.
-func (pc *PrometheusClient) QueryRange(query string, r v1.Range) (model.Value, error) {
-	result, _, err := pc.api.QueryRange(context.Background(), query, r)
+func (pc *PrometheusClient) QueryRange(datasource, query string, r v1.Range) (model.Value, error) {
+	promAPI, apiErr := pc.api(datasource)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	result, _, err := promAPI.QueryRange(context.Background(), query, r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute range query: %w", err)
 	}
@@ -12,13 +12,13 @@ import (
 // PrometheusServiceInterface defines the contract for Prometheus service operations.
 // It provides methods for executing both instant and range queries against Prometheus.
 type PrometheusServiceInterface interface {
-	// InstantQuery executes a PromQL query at the current time.
+	// InstantQuery executes a PromQL query at the current time against the named datasource.
 	// Returns the query result as a Prometheus model.Value.
-	InstantQuery(queryString string) (float64, error)
+	InstantQuery(datasource, queryString string) (model.Value, error)
 
-	// RangeQuery executes a PromQL query over a specified time range.
+	// RangeQuery executes a PromQL query over a specified time range against the named datasource.
 	// Returns the query result as a Prometheus model.Value.
-	RangeQuery(queryString string, start, end time.Time, step time.Duration) (model.Value, error)
+	RangeQuery(datasource, queryString string, start, end time.Time, step time.Duration) (model.Value, error)
 }
 
 // PrometheusService implements PrometheusServiceInterface to provide a high-level interface
@@ -46,13 +46,14 @@ func NewPrometheusService(client PrometheusClientInterface) *PrometheusService {
 // without requiring the caller to specify a timestamp.
 //
 // Parameters:
+//   - datasource: The name of the datasource to query, the default one when empty
 //   - queryString: The PromQL query to execute
 //
 // Returns:
 //   - model.Value: The query result in Prometheus model format
 //   - error: Any error that occurred during query execution
-func (ps *PrometheusService) InstantQuery(queryString string) (float64, error) {
-	return ps.client.Query(queryString, time.Now())
+func (ps *PrometheusService) InstantQuery(datasource, queryString string) (model.Value, error) {
+	return ps.client.Query(datasource, queryString, time.Now())
 }
 
 // RangeQuery executes a PromQL query over a specified time range.
@@ -60,6 +61,7 @@ func (ps *PrometheusService) InstantQuery(queryString string) (float64, error) {
 // by accepting start time, end time, and step duration as separate parameters.
 //
 // Parameters:
+//   - datasource: The name of the datasource to query, the default one when empty
 //   - queryString: The PromQL query to execute
 //   - start: The start time of the query range
 //   - end: The end time of the query range
@@ -68,11 +70,11 @@ func (ps *PrometheusService) InstantQuery(queryString string) (float64, error) {
 // Returns:
 //   - model.Value: The query result in Prometheus model format
 //   - error: Any error that occurred during query execution
-func (ps *PrometheusService) RangeQuery(queryString string, start, end time.Time, step time.Duration) (model.Value, error) {
+func (ps *PrometheusService) RangeQuery(datasource, queryString string, start, end time.Time, step time.Duration) (model.Value, error) {
 	r := v1.Range{
 		Start: start,
 		End:   end,
 		Step:  step,
 	}
-	return ps.client.QueryRange(queryString, r)
+	return ps.client.QueryRange(datasource, queryString, r)
 }
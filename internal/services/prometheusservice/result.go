@@ -0,0 +1,122 @@
+package prometheusservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/prometheus/common/model"
+)
+
+// Sample is the JSON representation of a single Prometheus data point.
+// Value is nil when Prometheus returns NaN or ±Inf, which cannot be represented in JSON.
+type Sample struct {
+	Timestamp float64  `json:"timestamp"`
+	Value     *float64 `json:"value"`
+}
+
+// InstantSeries is the JSON representation of a series of an instant vector.
+type InstantSeries struct {
+	Metric    map[string]string `json:"metric"`
+	Timestamp float64           `json:"timestamp"`
+	Value     *float64          `json:"value"`
+}
+
+// RangeSeries is the JSON representation of a series of a range matrix.
+type RangeSeries struct {
+	Metric map[string]string `json:"metric"`
+	Values []Sample          `json:"values"`
+}
+
+// ToJSON converts a Prometheus query result into plain JSON so that it can be inspected with jsonPath.
+//
+// The output depends on the result type:
+//   - vector: a list of {"metric": {labels}, "timestamp": <unix seconds>, "value": <number>}
+//   - matrix: a list of {"metric": {labels}, "values": [{"timestamp": <unix seconds>, "value": <number>}]}
+//   - scalar: {"timestamp": <unix seconds>, "value": <number>}
+//   - string: {"timestamp": <unix seconds>, "value": <string>}
+//
+// Parameters:
+//   - value: The query result in Prometheus model format
+//
+// Returns:
+//   - []byte: The JSON encoded result
+//   - error: Any error that occurred during the conversion
+func ToJSON(value model.Value) ([]byte, error) {
+	switch v := value.(type) {
+	case model.Vector:
+		series := make([]InstantSeries, len(v))
+		for i, sample := range v {
+			series[i] = InstantSeries{
+				Metric:    labels(sample.Metric),
+				Timestamp: unixSeconds(sample.Timestamp),
+				Value:     sampleValue(sample.Value),
+			}
+		}
+		return json.Marshal(series)
+	case model.Matrix:
+		series := make([]RangeSeries, len(v))
+		for i, stream := range v {
+			values := make([]Sample, len(stream.Values))
+			for j, pair := range stream.Values {
+				values[j] = Sample{Timestamp: unixSeconds(pair.Timestamp), Value: sampleValue(pair.Value)}
+			}
+			series[i] = RangeSeries{Metric: labels(stream.Metric), Values: values}
+		}
+		return json.Marshal(series)
+	case *model.Scalar:
+		return json.Marshal(Sample{Timestamp: unixSeconds(v.Timestamp), Value: sampleValue(v.Value)})
+	case *model.String:
+		return json.Marshal(map[string]interface{}{"timestamp": unixSeconds(v.Timestamp), "value": v.Value})
+	case nil:
+		return nil, fmt.Errorf("empty query result")
+	default:
+		return nil, fmt.Errorf("unsupported query result type: %s", value.Type())
+	}
+}
+
+// ToScalar reduces an instant query result to a single number.
+// For vectors the value of the last sample is returned and an empty vector is reported as 0.
+//
+// Parameters:
+//   - value: The query result in Prometheus model format
+//
+// Returns:
+//   - float64: The reduced value
+//   - error: Returned when the result is not a vector nor a scalar
+func ToScalar(value model.Value) (float64, error) {
+	switch v := value.(type) {
+	case model.Vector:
+		response := 0.0
+		for _, sample := range v {
+			response = float64(sample.Value)
+		}
+		return response, nil
+	case *model.Scalar:
+		return float64(v.Value), nil
+	case nil:
+		return 0, fmt.Errorf("empty query result")
+	default:
+		return 0, fmt.Errorf("query result of type %s cannot be reduced to a single value", value.Type())
+	}
+}
+
+func labels(metric model.Metric) map[string]string {
+	result := make(map[string]string, len(metric))
+	for name, value := range metric {
+		result[string(name)] = string(value)
+	}
+	return result
+}
+
+func unixSeconds(t model.Time) float64 {
+	return float64(t) / 1000
+}
+
+func sampleValue(v model.SampleValue) *float64 {
+	f := float64(v)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+	return &f
+}
@@ -0,0 +1,113 @@
+package prometheusservice_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/motain/of-catalog/internal/services/prometheusservice"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     model.Value
+		expected  string
+		expectErr bool
+	}{
+		{
+			name: "vector keeps labels and values of every series",
+			value: model.Vector{
+				{Metric: model.Metric{"namespace": "tags-api", "severity": "Critical"}, Value: 2, Timestamp: 1700000000000},
+				{Metric: model.Metric{"namespace": "tags-api", "severity": "High"}, Value: 5, Timestamp: 1700000000000},
+			},
+			expected: `[{"metric":{"namespace":"tags-api","severity":"Critical"},"timestamp":1700000000,"value":2},` +
+				`{"metric":{"namespace":"tags-api","severity":"High"},"timestamp":1700000000,"value":5}]`,
+		},
+		{
+			name: "matrix keeps every sample and maps NaN to null",
+			value: model.Matrix{
+				{
+					Metric: model.Metric{"job": "api"},
+					Values: []model.SamplePair{
+						{Timestamp: 1700000000000, Value: 0.5},
+						{Timestamp: 1700003600000, Value: model.SampleValue(math.NaN())},
+					},
+				},
+			},
+			expected: `[{"metric":{"job":"api"},"values":[{"timestamp":1700000000,"value":0.5},{"timestamp":1700003600,"value":null}]}]`,
+		},
+		{
+			name:     "empty vector",
+			value:    model.Vector{},
+			expected: `[]`,
+		},
+		{
+			name:     "scalar",
+			value:    &model.Scalar{Value: 42, Timestamp: 1700000000500},
+			expected: `{"timestamp":1700000000.5,"value":42}`,
+		},
+		{
+			name:      "nil result",
+			value:     nil,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := prometheusservice.ToJSON(tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestToScalar(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     model.Value
+		expected  float64
+		expectErr bool
+	}{
+		{
+			name:     "vector returns the last sample",
+			value:    model.Vector{{Value: 1}, {Value: 3}},
+			expected: 3,
+		},
+		{
+			name:     "empty vector",
+			value:    model.Vector{},
+			expected: 0,
+		},
+		{
+			name:     "scalar",
+			value:    &model.Scalar{Value: 7},
+			expected: 7,
+		},
+		{
+			name:      "matrix cannot be reduced",
+			value:     model.Matrix{},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := prometheusservice.ToScalar(tt.value)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
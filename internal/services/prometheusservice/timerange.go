@@ -0,0 +1,96 @@
+package prometheusservice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+// DefaultRangeStep is the resolution used by range queries not declaring a step.
+const DefaultRangeStep = 5 * time.Minute
+
+// ParseRange resolves the boundaries and resolution of a range query.
+//
+// start and end accept:
+//   - an empty string or "now", meaning the reference time
+//   - a duration relative to the reference time, e.g. "-7d", "now-1h" or "+30m"
+//   - an RFC3339 timestamp, e.g. "2025-01-01T00:00:00Z"
+//   - a unix timestamp in seconds
+//
+// step accepts a Prometheus duration (e.g. "1h", "5m") and defaults to DefaultRangeStep.
+//
+// Parameters:
+//   - start: The start of the range
+//   - end: The end of the range
+//   - step: The time step between data points
+//   - now: The reference time for relative expressions
+//
+// Returns:
+//   - time.Time: The resolved start time
+//   - time.Time: The resolved end time
+//   - time.Duration: The resolved step
+//   - error: Returned when an expression cannot be parsed or the range is empty
+func ParseRange(start, end, step string, now time.Time) (time.Time, time.Time, time.Duration, error) {
+	startTime, startErr := ParseTime(start, now)
+	if startErr != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid start: %w", startErr)
+	}
+
+	endTime, endErr := ParseTime(end, now)
+	if endErr != nil {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid end: %w", endErr)
+	}
+
+	if !startTime.Before(endTime) {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("start %s must be before end %s", startTime, endTime)
+	}
+
+	stepDuration := DefaultRangeStep
+	if step != "" {
+		parsedStep, stepErr := model.ParseDuration(step)
+		if stepErr != nil {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid step: %w", stepErr)
+		}
+		stepDuration = time.Duration(parsedStep)
+	}
+
+	if stepDuration <= 0 {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("step must be positive")
+	}
+
+	return startTime, endTime, stepDuration, nil
+}
+
+// ParseTime resolves a single time expression, see ParseRange for the accepted formats.
+func ParseTime(expr string, now time.Time) (time.Time, error) {
+	expr = strings.TrimSpace(expr)
+	relative := strings.TrimSpace(strings.TrimPrefix(expr, "now"))
+
+	if relative == "" {
+		return now, nil
+	}
+
+	if strings.HasPrefix(relative, "-") || strings.HasPrefix(relative, "+") {
+		offset, durationErr := model.ParseDuration(strings.TrimSpace(relative[1:]))
+		if durationErr != nil {
+			return time.Time{}, durationErr
+		}
+		if relative[0] == '-' {
+			return now.Add(-time.Duration(offset)), nil
+		}
+		return now.Add(time.Duration(offset)), nil
+	}
+
+	if parsed, rfcErr := time.Parse(time.RFC3339, expr); rfcErr == nil {
+		return parsed, nil
+	}
+
+	if seconds, unixErr := strconv.ParseFloat(expr, 64); unixErr == nil {
+		return time.UnixMilli(int64(seconds * 1000)), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported time expression %q", expr)
+}
@@ -0,0 +1,82 @@
+package prometheusservice_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/motain/of-catalog/internal/services/prometheusservice"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		start         string
+		end           string
+		step          string
+		expectedStart time.Time
+		expectedEnd   time.Time
+		expectedStep  time.Duration
+		expectErr     bool
+	}{
+		{
+			name:          "relative start with default end and step",
+			start:         "-7d",
+			expectedStart: now.Add(-7 * 24 * time.Hour),
+			expectedEnd:   now,
+			expectedStep:  prometheusservice.DefaultRangeStep,
+		},
+		{
+			name:          "relative to now",
+			start:         "now-2h",
+			end:           "now - 1h",
+			step:          "1m",
+			expectedStart: now.Add(-2 * time.Hour),
+			expectedEnd:   now.Add(-time.Hour),
+			expectedStep:  time.Minute,
+		},
+		{
+			name:          "absolute timestamps",
+			start:         "2025-03-01T00:00:00Z",
+			end:           "1741392000",
+			step:          "1h",
+			expectedStart: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Unix(1741392000, 0),
+			expectedStep:  time.Hour,
+		},
+		{
+			name:      "start after end",
+			start:     "-1h",
+			end:       "-2h",
+			expectErr: true,
+		},
+		{
+			name:      "invalid duration",
+			start:     "-7days",
+			expectErr: true,
+		},
+		{
+			name:      "invalid step",
+			start:     "-1d",
+			step:      "often",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, step, err := prometheusservice.ParseRange(tt.start, tt.end, tt.step, now)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, tt.expectedStart.Equal(start), "start: expected %s, got %s", tt.expectedStart, start)
+			assert.True(t, tt.expectedEnd.Equal(end), "end: expected %s, got %s", tt.expectedEnd, end)
+			assert.Equal(t, tt.expectedStep, step)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package awsutils
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
@@ -14,6 +15,9 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	sigv4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 // SigV4RoundTripper implements http.RoundTripper and signs AWS requests using AWS Signature Version 4.
@@ -184,3 +188,34 @@ func hashPayload(body io.ReadSeeker) string {
 	h.Write(data)
 	return hex.EncodeToString(h.Sum(nil))
 }
+
+// LoadConfig loads the AWS configuration from the environment for the given region.
+// When roleARN is set the credentials of the configuration are replaced by the ones of the assumed role,
+// otherwise the existing credentials are verified upfront to fail fast on misconfigured environments.
+//
+// Parameters:
+//   - ctx: Context for the AWS calls
+//   - region: The AWS region (e.g., "eu-west-1")
+//   - roleARN: The ARN of the role to assume, empty to use the existing credentials
+//
+// Returns:
+//   - aws.Config: The AWS configuration with resolved credentials
+//   - error: Any error that occurred while loading the configuration or verifying the credentials
+func LoadConfig(ctx context.Context, region, roleARN string) (aws.Config, error) {
+	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if roleARN != "" {
+		awsCfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsCfg), roleARN))
+		return awsCfg, nil
+	}
+
+	stsClient := sts.NewFromConfig(awsCfg)
+	if _, identityErr := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{}); identityErr != nil {
+		return aws.Config{}, fmt.Errorf("failed to get caller identity: %w", identityErr)
+	}
+
+	return awsCfg, nil
+}
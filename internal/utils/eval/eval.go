@@ -1,71 +1,48 @@
 package eval
 
 import (
-	"fmt"
-	"strconv"
+	"errors"
 	"strings"
 )
 
-func Expression(expr string) (bool, error) {
-	expr = strings.TrimSpace(expr)
-	op, err := findOperator(expr)
-	if err != nil {
-		return false, err
-	}
+// FormulaVariable is the name under which formulas reference the value they validate.
+const FormulaVariable = "value"
 
-	left, right, err := splitOperands(expr, op)
+// Expression evaluates a constant boolean expression, e.g. "4 > 3 and 2 != 1".
+func Expression(expr string) (bool, error) {
+	program, err := Parse(expr)
 	if err != nil {
 		return false, err
 	}
 
-	return evaluate(left, right, op)
+	return program.EvalBool(nil)
 }
 
-func findOperator(expr string) (string, error) {
-	operators := []string{">=", "<=", ">", "<", "==", "!="}
-	for _, o := range operators {
-		if strings.Contains(expr, o) {
-			return o, nil
+// Formula parses the pattern of a formula validation.
+// A pattern starting with a comparison operator is a partial expression applied to the value,
+// e.g. "< 1" is equivalent to "value < 1". Any other pattern must reference the value explicitly,
+// e.g. "value >= 2 and value <= 10" or `value == "go"`.
+func Formula(pattern string) (*Program, error) {
+	trimmed := strings.TrimSpace(pattern)
+	for _, op := range []string{"==", "!=", "<", ">"} {
+		if !strings.HasPrefix(trimmed, op) {
+			continue
 		}
-	}
-	return "", fmt.Errorf("no valid operator found in expression")
-}
 
-func splitOperands(expr, op string) (float64, float64, error) {
-	parts := strings.Split(expr, op)
-	if len(parts) != 2 {
-		return 0, 0, fmt.Errorf("invalid expression format")
-	}
-
-	leftStr, rightStr := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
-	left, err := strconv.ParseFloat(leftStr, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid left operand: %v", err)
-	}
-
-	right, err := strconv.ParseFloat(rightStr, 64)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid right operand: %v", err)
+		prefix := FormulaVariable + " "
+		program, err := Parse(prefix + trimmed)
+		// Report positions within the pattern rather than within the completed expression
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, &SyntaxError{Pos: max(syntaxErr.Pos-len(prefix), 0), Msg: syntaxErr.Msg}
+		}
+		return program, err
 	}
 
-	return left, right, nil
+	return Parse(trimmed)
 }
 
-func evaluate(left, right float64, op string) (bool, error) {
-	switch op {
-	case ">":
-		return left > right, nil
-	case "<":
-		return left < right, nil
-	case ">=":
-		return left >= right, nil
-	case "<=":
-		return left <= right, nil
-	case "==":
-		return left == right, nil
-	case "!=":
-		return left != right, nil
-	default:
-		return false, fmt.Errorf("unsupported operator")
-	}
+// EvalFormula evaluates a formula parsed with Formula against a value.
+func EvalFormula(program *Program, value interface{}) (bool, error) {
+	return program.EvalBool(map[string]interface{}{FormulaVariable: value})
 }
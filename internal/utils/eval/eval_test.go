@@ -30,6 +30,13 @@ func TestExpression(t *testing.T) {
 		{"5 > ", false, true},
 		{" > 5", false, true},
 		{"5 >> 3", false, true},
+		{"-3 < -2", true, false},
+		{"10 >= 5 and 2 > 3", false, false},
+		{"10 >= 5 || 2 > 3", true, false},
+		{"(1 + 2) * 3 == 9", true, false},
+		{"2 + 3 * 4 >= 14", true, false},
+		{`"a b" == 'a b'`, true, false},
+		{"3 + 4", false, true},
 	}
 
 	for _, tt := range tests {
@@ -44,3 +51,44 @@ func TestExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestFormula(t *testing.T) {
+	tests := []struct {
+		pattern     string
+		value       interface{}
+		expected    bool
+		expectedErr string
+	}{
+		{pattern: "< 1", value: 0.0, expected: true},
+		{pattern: "< 1", value: 1.0, expected: false},
+		{pattern: ">= -5", value: -3.0, expected: true},
+		{pattern: ">= 0.995", value: "0.999", expected: true},
+		{pattern: "== 3", value: 3, expected: true},
+		{pattern: `== "go"`, value: "go", expected: true},
+		{pattern: `!= "payments team"`, value: "payments team", expected: false},
+		{pattern: "== true", value: true, expected: true},
+		{pattern: "value >= 2 and value <= 10", value: 11.0, expected: false},
+		{pattern: "value % 2 == 0 || value < 0", value: 4.0, expected: true},
+		{pattern: "> 1", value: "high", expectedErr: `"high" is not a number`},
+		{pattern: "value + 1", value: 1.0, expectedErr: `expression "value + 1" returned 2, expected a boolean`},
+		{pattern: "> ", value: 1.0, expectedErr: "syntax error at position 1: unexpected end of expression"},
+		{pattern: "=> 3", value: 1.0, expectedErr: `syntax error at position 0: unexpected character '='`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			formula, err := eval.Formula(tt.pattern)
+			if err == nil {
+				var result bool
+				result, err = eval.EvalFormula(formula, tt.value)
+				if tt.expectedErr == "" {
+					assert.NoError(t, err)
+					assert.Equal(t, tt.expected, result)
+					return
+				}
+			}
+
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
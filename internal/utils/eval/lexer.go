@@ -0,0 +1,137 @@
+package eval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	numberToken
+	stringToken
+	identToken
+	operatorToken
+	lparenToken
+	rparenToken
+	commaToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+// operators are matched longest first, so that ">=" is never read as ">" followed by "=".
+var operators = []string{"&&", "||", "==", "!=", ">=", "<=", ">", "<", "+", "-", "*", "/", "%", "!"}
+
+var wordOperators = map[string]string{"and": "&&", "or": "||", "not": "!"}
+
+func tokenize(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(expr)
+
+	for pos := 0; pos < len(runes); {
+		r := runes[pos]
+		switch {
+		case unicode.IsSpace(r):
+			pos++
+		case unicode.IsDigit(r) || r == '.' && pos+1 < len(runes) && unicode.IsDigit(runes[pos+1]):
+			start := pos
+			for pos < len(runes) && (unicode.IsDigit(runes[pos]) || runes[pos] == '.' || runes[pos] == '_') {
+				pos++
+			}
+			// Exponents, e.g. 1e-3
+			if pos < len(runes) && (runes[pos] == 'e' || runes[pos] == 'E') {
+				next := pos + 1
+				if next < len(runes) && (runes[next] == '+' || runes[next] == '-') {
+					next++
+				}
+				if next < len(runes) && unicode.IsDigit(runes[next]) {
+					pos = next
+					for pos < len(runes) && unicode.IsDigit(runes[pos]) {
+						pos++
+					}
+				}
+			}
+			text := string(runes[start:pos])
+			num, parseErr := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+			if parseErr != nil {
+				return nil, &SyntaxError{Pos: start, Msg: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, token{kind: numberToken, text: text, num: num, pos: start})
+		case r == '"' || r == '\'':
+			start := pos
+			value, end, stringErr := readString(runes, pos)
+			if stringErr != nil {
+				return nil, stringErr
+			}
+			pos = end
+			tokens = append(tokens, token{kind: stringToken, text: value, pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := pos
+			for pos < len(runes) && (unicode.IsLetter(runes[pos]) || unicode.IsDigit(runes[pos]) || runes[pos] == '_') {
+				pos++
+			}
+			text := string(runes[start:pos])
+			if op, isOperator := wordOperators[text]; isOperator {
+				tokens = append(tokens, token{kind: operatorToken, text: op, pos: start})
+				continue
+			}
+			tokens = append(tokens, token{kind: identToken, text: text, pos: start})
+		case r == '(':
+			tokens = append(tokens, token{kind: lparenToken, text: "(", pos: pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, token{kind: rparenToken, text: ")", pos: pos})
+			pos++
+		case r == ',':
+			tokens = append(tokens, token{kind: commaToken, text: ",", pos: pos})
+			pos++
+		default:
+			op := matchOperator(runes[pos:])
+			if op == "" {
+				return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, token{kind: operatorToken, text: op, pos: pos})
+			pos += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: eofToken, pos: len(runes)}), nil
+}
+
+func matchOperator(runes []rune) string {
+	for _, op := range operators {
+		if strings.HasPrefix(string(runes[:min(len(runes), 2)]), op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// readString reads a quoted string starting at pos, supporting backslash escapes of the quote and
+// of the backslash. It returns the unquoted value and the position following the closing quote.
+func readString(runes []rune, pos int) (string, int, error) {
+	quote := runes[pos]
+	var value strings.Builder
+	for i := pos + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) && (runes[i+1] == quote || runes[i+1] == '\\') {
+				i++
+			}
+			value.WriteRune(runes[i])
+		case quote:
+			return value.String(), i + 1, nil
+		default:
+			value.WriteRune(runes[i])
+		}
+	}
+	return "", 0, &SyntaxError{Pos: pos, Msg: "unterminated string"}
+}
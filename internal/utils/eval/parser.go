@@ -0,0 +1,284 @@
+package eval
+
+import (
+	"fmt"
+)
+
+// maxDepth bounds the nesting of expressions, protecting the evaluator from stack exhaustion.
+const maxDepth = 64
+
+// SyntaxError reports an invalid expression and the position (in characters) at which it was detected.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Msg)
+}
+
+type node interface{}
+
+type literalNode struct {
+	value interface{}
+}
+
+type identNode struct {
+	name string
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+	pos         int
+}
+
+type callNode struct {
+	name string
+	args []node
+	pos  int
+}
+
+// parser is a recursive descent parser, from the lowest to the highest precedence:
+//
+//	or         = and { ("||" | "or") and }
+//	and        = not { ("&&" | "and") not }
+//	not        = ("!" | "not") not | comparison
+//	comparison = additive [ ("==" | "!=" | "<" | "<=" | ">" | ">=") additive ]
+//	additive   = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/" | "%") unary }
+//	unary      = "-" unary | primary
+//	primary    = number | string | "true" | "false" | ident | ident "(" [ or { "," or } ] ")" | "(" or ")"
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func parse(expr string) (node, error) {
+	tokens, tokenizeErr := tokenize(expr)
+	if tokenizeErr != nil {
+		return nil, tokenizeErr
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == eofToken {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty expression"}
+	}
+
+	root, parseErr := p.parseOr()
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	if next := p.peek(); next.kind != eofToken {
+		return nil, &SyntaxError{Pos: next.pos, Msg: fmt.Sprintf("unexpected %q", next.text)}
+	}
+
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != eofToken {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) acceptOperator(ops ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != operatorToken {
+		return t, false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return p.next(), true
+		}
+	}
+	return t, false
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxDepth {
+		return &SyntaxError{Pos: p.peek().pos, Msg: "expression too deeply nested"}
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) parseBinary(operand func() (node, error), ops ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, found := p.acceptOperator(ops...)
+		if !found {
+			return left, nil
+		}
+
+		right, rightErr := operand()
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		left = &binaryNode{op: op.text, left: left, right: right, pos: op.pos}
+	}
+}
+
+func (p *parser) parseOr() (node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary(p.parseNot, "&&")
+}
+
+func (p *parser) parseNot() (node, error) {
+	if _, found := p.acceptOperator("!"); found {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "!", operand: operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	op, found := p.acceptOperator("==", "!=", "<", "<=", ">", ">=")
+	if !found {
+		return left, nil
+	}
+
+	right, rightErr := p.parseAdditive()
+	if rightErr != nil {
+		return nil, rightErr
+	}
+
+	if chained, isChained := p.acceptOperator("==", "!=", "<", "<=", ">", ">="); isChained {
+		return nil, &SyntaxError{Pos: chained.pos, Msg: fmt.Sprintf("unexpected %q, comparisons cannot be chained", chained.text)}
+	}
+
+	return &binaryNode{op: op.text, left: left, right: right, pos: op.pos}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary(p.parseTerm, "+", "-")
+}
+
+func (p *parser) parseTerm() (node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, found := p.acceptOperator("-"); found {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case numberToken:
+		return &literalNode{value: t.num}, nil
+	case stringToken:
+		return &literalNode{value: t.text}, nil
+	case identToken:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		}
+
+		if p.peek().kind == lparenToken {
+			return p.parseCall(t)
+		}
+		return &identNode{name: t.text}, nil
+	case lparenToken:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != rparenToken {
+			return nil, &SyntaxError{Pos: closing.pos, Msg: "missing closing parenthesis"}
+		}
+		return inner, nil
+	case eofToken:
+		return nil, &SyntaxError{Pos: t.pos, Msg: "unexpected end of expression"}
+	default:
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+	}
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	if _, known := functions[name.text]; !known {
+		return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("unknown function %s", name.text)}
+	}
+
+	p.next() // (
+	call := &callNode{name: name.text, pos: name.pos}
+	if p.peek().kind == rparenToken {
+		p.next()
+		return call, nil
+	}
+
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+
+		switch t := p.next(); t.kind {
+		case commaToken:
+			continue
+		case rparenToken:
+			return call, nil
+		default:
+			return nil, &SyntaxError{Pos: t.pos, Msg: "missing closing parenthesis"}
+		}
+	}
+}
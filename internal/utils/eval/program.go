@@ -0,0 +1,419 @@
+package eval
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Program is a parsed expression that can be evaluated many times with different variables.
+//
+// Expressions support:
+//   - numbers (3, -1.5, 1e3), strings ("a" or 'a'), true and false
+//   - variables, referenced by name ([A-Za-z_][A-Za-z0-9_]*)
+//   - arithmetic: + - * / % and parentheses
+//   - comparison: == != < <= > >=
+//   - boolean logic: && || ! and their word forms and, or, not
+//   - the functions len, sum, min, max, abs and round
+//
+// Expressions cannot assign variables, loop or call anything but the functions above.
+type Program struct {
+	source string
+	root   node
+}
+
+// Parse parses an expression, reporting syntax errors with their position.
+func Parse(expr string) (*Program, error) {
+	root, err := parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Program{source: expr, root: root}, nil
+}
+
+// String returns the source of the program.
+func (p *Program) String() string {
+	return p.source
+}
+
+// Variables returns the sorted names of the variables referenced by the program.
+func (p *Program) Variables() []string {
+	names := make(map[string]bool)
+	var walk func(n node)
+	walk = func(n node) {
+		switch v := n.(type) {
+		case *identNode:
+			names[v.name] = true
+		case *unaryNode:
+			walk(v.operand)
+		case *binaryNode:
+			walk(v.left)
+			walk(v.right)
+		case *callNode:
+			for _, arg := range v.args {
+				walk(arg)
+			}
+		}
+	}
+	walk(p.root)
+
+	variables := make([]string, 0, len(names))
+	for name := range names {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// Eval evaluates the program.
+//
+// Variables can hold numbers of any type, booleans, strings or slices. In arithmetic and ordering
+// comparisons booleans count as 1 and 0, slices as their length and strings must be numeric.
+//
+// Parameters:
+//   - vars: The values of the variables referenced by the program
+//
+// Returns:
+//   - interface{}: The result, a float64, a bool, a string or a []interface{}
+//   - error: Returned when a variable is missing or an operation is invalid for its operands
+func (p *Program) Eval(vars map[string]interface{}) (interface{}, error) {
+	return evalNode(p.root, vars)
+}
+
+// EvalBool evaluates a program expected to return a boolean.
+func (p *Program) EvalBool(vars map[string]interface{}) (bool, error) {
+	result, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+
+	value, isBool := result.(bool)
+	if !isBool {
+		return false, fmt.Errorf("expression %q returned %v, expected a boolean", p.source, result)
+	}
+	return value, nil
+}
+
+func evalNode(n node, vars map[string]interface{}) (interface{}, error) {
+	switch v := n.(type) {
+	case *literalNode:
+		return v.value, nil
+	case *identNode:
+		value, found := vars[v.name]
+		if !found {
+			return nil, fmt.Errorf("unknown variable %s", v.name)
+		}
+		return normalize(value), nil
+	case *unaryNode:
+		return evalUnary(v, vars)
+	case *binaryNode:
+		return evalBinary(v, vars)
+	case *callNode:
+		args := make([]interface{}, len(v.args))
+		for i, arg := range v.args {
+			value, err := evalNode(arg, vars)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = value
+		}
+		result, err := functions[v.name](args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", v.name, err)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported expression node %T", n)
+	}
+}
+
+func evalUnary(n *unaryNode, vars map[string]interface{}) (interface{}, error) {
+	operand, err := evalNode(n.operand, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "!" {
+		value, boolErr := toBool(operand)
+		if boolErr != nil {
+			return nil, boolErr
+		}
+		return !value, nil
+	}
+
+	value, numErr := toNumber(operand)
+	if numErr != nil {
+		return nil, numErr
+	}
+	return -value, nil
+}
+
+func evalBinary(n *binaryNode, vars map[string]interface{}) (interface{}, error) {
+	left, leftErr := evalNode(n.left, vars)
+	if leftErr != nil {
+		return nil, leftErr
+	}
+
+	// Boolean operators short-circuit
+	if n.op == "&&" || n.op == "||" {
+		leftValue, boolErr := toBool(left)
+		if boolErr != nil {
+			return nil, boolErr
+		}
+		if (n.op == "&&") != leftValue {
+			return leftValue, nil
+		}
+
+		right, rightErr := evalNode(n.right, vars)
+		if rightErr != nil {
+			return nil, rightErr
+		}
+		return toBool(right)
+	}
+
+	right, rightErr := evalNode(n.right, vars)
+	if rightErr != nil {
+		return nil, rightErr
+	}
+
+	switch n.op {
+	case "==", "!=":
+		equal, err := equals(left, right)
+		if err != nil {
+			return nil, err
+		}
+		return equal == (n.op == "=="), nil
+	}
+
+	l, lErr := toNumber(left)
+	if lErr != nil {
+		return nil, lErr
+	}
+	r, rErr := toNumber(right)
+	if rErr != nil {
+		return nil, rErr
+	}
+
+	switch n.op {
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero at position %d", n.pos)
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero at position %d", n.pos)
+		}
+		return math.Mod(l, r), nil
+	default:
+		return nil, fmt.Errorf("unsupported operator %s", n.op)
+	}
+}
+
+// equals compares strings and booleans by value, anything else as numbers.
+func equals(left, right interface{}) (bool, error) {
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+	if leftIsString && rightIsString {
+		return leftString == rightString, nil
+	}
+
+	leftBool, leftIsBool := left.(bool)
+	rightBool, rightIsBool := right.(bool)
+	if leftIsBool && rightIsBool {
+		return leftBool == rightBool, nil
+	}
+
+	l, lErr := toNumber(left)
+	if lErr != nil {
+		return false, lErr
+	}
+	r, rErr := toNumber(right)
+	if rErr != nil {
+		return false, rErr
+	}
+	return l == r, nil
+}
+
+// normalize converts the values of variables to the types handled by the evaluator.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string, float64, []interface{}:
+		return v
+	case []byte:
+		return string(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = normalize(rv.Index(i).Interface())
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+func toNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case []interface{}:
+		return float64(len(v)), nil
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", v)
+		}
+		return parsed, nil
+	case nil:
+		return 0, fmt.Errorf("null is not a number")
+	default:
+		return 0, fmt.Errorf("%v (%T) is not a number", v, v)
+	}
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	default:
+		return false, fmt.Errorf("%v (%T) is not a boolean", v, v)
+	}
+}
+
+var functions = map[string]func(args []interface{}) (interface{}, error){
+	"len": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		switch v := args[0].(type) {
+		case []interface{}:
+			return float64(len(v)), nil
+		case string:
+			return float64(len([]rune(v))), nil
+		default:
+			return nil, fmt.Errorf("%v (%T) has no length", v, v)
+		}
+	},
+	"sum": func(args []interface{}) (interface{}, error) {
+		values, err := numbers(args)
+		if err != nil {
+			return nil, err
+		}
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum, nil
+	},
+	"min": func(args []interface{}) (interface{}, error) {
+		values, err := numbers(args)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no values")
+		}
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+		return result, nil
+	},
+	"max": func(args []interface{}) (interface{}, error) {
+		values, err := numbers(args)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no values")
+		}
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Max(result, v)
+		}
+		return result, nil
+	},
+	"abs": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		value, err := toNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return math.Abs(value), nil
+	},
+	"round": func(args []interface{}) (interface{}, error) {
+		if len(args) != 1 && len(args) != 2 {
+			return nil, fmt.Errorf("expected 1 or 2 arguments, got %d", len(args))
+		}
+		value, err := toNumber(args[0])
+		if err != nil {
+			return nil, err
+		}
+		precision := 0.0
+		if len(args) == 2 {
+			if precision, err = toNumber(args[1]); err != nil {
+				return nil, err
+			}
+		}
+		scale := math.Pow(10, precision)
+		return math.Round(value*scale) / scale, nil
+	},
+}
+
+// numbers flattens the arguments of the aggregate functions, which accept lists as well as
+// several values, e.g. sum(a) or sum(a, b, 1).
+func numbers(args []interface{}) ([]float64, error) {
+	values := make([]float64, 0, len(args))
+	for _, arg := range args {
+		items, isList := arg.([]interface{})
+		if !isList {
+			items = []interface{}{arg}
+		}
+		for _, item := range items {
+			value, err := toNumber(item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
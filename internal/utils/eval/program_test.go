@@ -0,0 +1,106 @@
+package eval_test
+
+import (
+	"testing"
+
+	"github.com/motain/of-catalog/internal/utils/eval"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgram_Eval(t *testing.T) {
+	vars := map[string]interface{}{
+		"a":         2,
+		"b":         3.5,
+		"c":         float32(0.5),
+		"passing":   []bool{true, false, true},
+		"workflows": []interface{}{"build", "deploy", "lint", "test"},
+		"alerts":    []interface{}{1.0, 2.0, 4.0},
+		"has_slo":   true,
+		"tier":      "1",
+		"language":  "go",
+	}
+
+	tests := []struct {
+		expr        string
+		expected    interface{}
+		expectedErr string
+	}{
+		{expr: "(a + b) / c * 100", expected: 1100.0},
+		{expr: "a - -b", expected: 5.5},
+		{expr: "7 % 4 + 2 * 3", expected: 9.0},
+		{expr: "1_000 + 1e-3", expected: 1000.001},
+		{expr: "len(passing) / len(workflows)", expected: 0.75},
+		{expr: "passing / workflows * 100", expected: 75.0},
+		{expr: "sum(alerts) + max(alerts, 10) - min(a, b)", expected: 15.0},
+		{expr: "round(10 / 3, 2)", expected: 3.33},
+		{expr: "abs(a - b)", expected: 1.5},
+		{expr: "has_slo + has_slo", expected: 2.0},
+		{expr: "a >= 2 && b < 4", expected: true},
+		{expr: "a > 2 or not has_slo", expected: false},
+		{expr: "!(a == 2) || tier == 1", expected: true},
+		{expr: `language == "go" and tier != 'java'`, expected: true},
+		{expr: "has_slo == true", expected: true},
+		{expr: "false && unknown > 1", expected: false},
+		{expr: "a / (b - 3.5)", expectedErr: "division by zero at position 2"},
+		{expr: "language > 1", expectedErr: `"go" is not a number`},
+		{expr: "missing + 1", expectedErr: "unknown variable missing"},
+		{expr: "len(a)", expectedErr: "len: 2 (float64) has no length"},
+		{expr: "min()", expectedErr: "min: no values"},
+		{expr: "a && b", expected: true},
+		{expr: "language || true", expectedErr: `go (string) is not a boolean`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			program, parseErr := eval.Parse(tt.expr)
+			assert.NoError(t, parseErr)
+
+			result, err := program.Eval(vars)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			if expected, isNumber := tt.expected.(float64); isNumber {
+				assert.InDelta(t, expected, result, 1e-9)
+				return
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr              string
+		expectedVariables []string
+		expectedErr       string
+	}{
+		{expr: "(has_runbook + has_slo) / total * 100", expectedVariables: []string{"has_runbook", "has_slo", "total"}},
+		{expr: "max(a, b) > 3 and not c", expectedVariables: []string{"a", "b", "c"}},
+		{expr: "1 + 1", expectedVariables: []string{}},
+		{expr: "", expectedErr: "syntax error at position 0: empty expression"},
+		{expr: "a +", expectedErr: "syntax error at position 3: unexpected end of expression"},
+		{expr: "(a + b", expectedErr: "syntax error at position 6: missing closing parenthesis"},
+		{expr: "a = 1", expectedErr: `syntax error at position 2: unexpected character '='`},
+		{expr: "1 < a < 3", expectedErr: `syntax error at position 6: unexpected "<", comparisons cannot be chained`},
+		{expr: "a b", expectedErr: `syntax error at position 2: unexpected "b"`},
+		{expr: "exec('rm -rf /')", expectedErr: "syntax error at position 0: unknown function exec"},
+		{expr: `"unterminated`, expectedErr: "syntax error at position 0: unterminated string"},
+		{expr: "5 >> 3", expectedErr: `syntax error at position 3: unexpected ">"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			program, err := eval.Parse(tt.expr)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedVariables, program.Variables())
+		})
+	}
+}
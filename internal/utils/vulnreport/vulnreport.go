@@ -0,0 +1,333 @@
+package vulnreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Format is the format of a vulnerability or SBOM report.
+type Format string
+
+const (
+	AutoFormat      Format = "auto"
+	TrivyFormat     Format = "trivy"
+	GrypeFormat     Format = "grype"
+	CycloneDXFormat Format = "cyclonedx"
+	SPDXFormat      Format = "spdx"
+)
+
+// Severity is the normalized severity of a finding.
+type Severity string
+
+const (
+	Critical Severity = "critical"
+	High     Severity = "high"
+	Medium   Severity = "medium"
+	Low      Severity = "low"
+	Unknown  Severity = "unknown"
+)
+
+// severityRanks orders the severities, higher is worse.
+var severityRanks = map[Severity]int{Unknown: 0, Low: 1, Medium: 2, High: 3, Critical: 4}
+
+// Finding is a vulnerability affecting a package.
+type Finding struct {
+	ID           string   `json:"id"`
+	Package      string   `json:"package,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	FixedVersion string   `json:"fixedVersion,omitempty"`
+	Severity     Severity `json:"severity"`
+}
+
+// Summary is the normalized content of a report.
+type Summary struct {
+	Critical int       `json:"critical"`
+	High     int       `json:"high"`
+	Medium   int       `json:"medium"`
+	Low      int       `json:"low"`
+	Unknown  int       `json:"unknown"`
+	Total    int       `json:"total"`
+	Findings []Finding `json:"findings"`
+}
+
+// Parse reads a report and counts its findings by severity.
+// The same vulnerability reported more than once for the same package (e.g. by several targets
+// of a Trivy report) is counted once.
+//
+// Parameters:
+//   - format: The format of the report, AutoFormat or an empty string detect it from the content
+//   - data: The JSON encoded report
+//
+// Returns:
+//   - Summary: The findings and their counts by severity
+//   - error: Returned when the report is not valid JSON or its format is unknown
+func Parse(format Format, data []byte) (Summary, error) {
+	var report map[string]interface{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return Summary{}, fmt.Errorf("invalid report: %v", err)
+	}
+
+	if format == "" || format == AutoFormat {
+		format = Detect(report)
+	}
+
+	var findings []Finding
+	switch format {
+	case TrivyFormat:
+		findings = parseTrivy(report)
+	case GrypeFormat:
+		findings = parseGrype(report)
+	case CycloneDXFormat:
+		findings = parseCycloneDX(report)
+	case SPDXFormat:
+		findings = parseSPDX(report)
+	default:
+		return Summary{}, fmt.Errorf("unknown report format %q", format)
+	}
+
+	return summarize(findings), nil
+}
+
+// Detect guesses the format of a decoded report, returning an empty Format when it is not recognized.
+func Detect(report map[string]interface{}) Format {
+	switch {
+	case report["bomFormat"] == "CycloneDX":
+		return CycloneDXFormat
+	case report["spdxVersion"] != nil || strings.Contains(fmt.Sprintf("%v", report["@context"]), "spdx"):
+		return SPDXFormat
+	case report["matches"] != nil:
+		return GrypeFormat
+	case report["Results"] != nil || report["SchemaVersion"] != nil:
+		return TrivyFormat
+	default:
+		return ""
+	}
+}
+
+// AtLeast counts the findings of the summary with a severity of at least min.
+// An empty min counts every finding, unknown severities included.
+func (s Summary) AtLeast(min Severity) (int, error) {
+	if min == "" {
+		return s.Total, nil
+	}
+
+	minRank, known := severityRanks[Severity(strings.ToLower(string(min)))]
+	if !known {
+		return 0, fmt.Errorf("unknown severity %q", min)
+	}
+
+	count := 0
+	for _, finding := range s.Findings {
+		if severityRanks[finding.Severity] >= minRank {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// NormalizeSeverity maps the severities used by the scanners to critical, high, medium, low or unknown.
+// The CVSS "none" rating means no risk and is not counted as low.
+func NormalizeSeverity(severity string) Severity {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical":
+		return Critical
+	case "high", "important":
+		return High
+	case "medium", "moderate":
+		return Medium
+	case "low", "negligible", "info":
+		return Low
+	default:
+		return Unknown
+	}
+}
+
+func parseTrivy(report map[string]interface{}) []Finding {
+	findings := make([]Finding, 0)
+	for _, result := range list(report["Results"]) {
+		for _, vuln := range list(object(result)["Vulnerabilities"]) {
+			v := object(vuln)
+			findings = append(findings, Finding{
+				ID:           str(v["VulnerabilityID"]),
+				Package:      str(v["PkgName"]),
+				Version:      str(v["InstalledVersion"]),
+				FixedVersion: str(v["FixedVersion"]),
+				Severity:     NormalizeSeverity(str(v["Severity"])),
+			})
+		}
+	}
+	return findings
+}
+
+func parseGrype(report map[string]interface{}) []Finding {
+	findings := make([]Finding, 0)
+	for _, match := range list(report["matches"]) {
+		m := object(match)
+		vuln := object(m["vulnerability"])
+		artifact := object(m["artifact"])
+		fixedVersions := list(object(vuln["fix"])["versions"])
+
+		finding := Finding{
+			ID:       str(vuln["id"]),
+			Package:  str(artifact["name"]),
+			Version:  str(artifact["version"]),
+			Severity: NormalizeSeverity(str(vuln["severity"])),
+		}
+		if len(fixedVersions) > 0 {
+			finding.FixedVersion = str(fixedVersions[0])
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// parseCycloneDX reads the vulnerabilities of a CycloneDX document (1.4+), one finding per affected
+// component. A vulnerability rated by several sources takes its highest rating.
+func parseCycloneDX(report map[string]interface{}) []Finding {
+	components := make(map[string]map[string]interface{})
+	var collect func(items []interface{})
+	collect = func(items []interface{}) {
+		for _, item := range items {
+			c := object(item)
+			if ref := str(c["bom-ref"]); ref != "" {
+				components[ref] = c
+			}
+			collect(list(c["components"]))
+		}
+	}
+	collect(list(report["components"]))
+	if metadataComponent := object(object(report["metadata"])["component"]); str(metadataComponent["bom-ref"]) != "" {
+		components[str(metadataComponent["bom-ref"])] = metadataComponent
+	}
+
+	findings := make([]Finding, 0)
+	for _, vuln := range list(report["vulnerabilities"]) {
+		v := object(vuln)
+		severity := Unknown
+		for _, rating := range list(v["ratings"]) {
+			rated := NormalizeSeverity(str(object(rating)["severity"]))
+			if severityRanks[rated] > severityRanks[severity] {
+				severity = rated
+			}
+		}
+
+		affects := list(v["affects"])
+		if len(affects) == 0 {
+			findings = append(findings, Finding{ID: str(v["id"]), Severity: severity})
+			continue
+		}
+
+		for _, affected := range affects {
+			ref := str(object(affected)["ref"])
+			finding := Finding{ID: str(v["id"]), Package: ref, Severity: severity}
+			if component, found := components[ref]; found {
+				finding.Package = str(component["name"])
+				finding.Version = str(component["version"])
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// parseSPDX reads the vulnerabilities of an SPDX document. SPDX 3 documents carry them as security
+// assessment relationships, SPDX 2 documents can only reference advisories from their packages, these
+// findings have an unknown severity.
+func parseSPDX(report map[string]interface{}) []Finding {
+	findings := make([]Finding, 0)
+
+	for _, pkg := range list(report["packages"]) {
+		p := object(pkg)
+		for _, ref := range list(p["externalRefs"]) {
+			r := object(ref)
+			if !strings.EqualFold(str(r["referenceCategory"]), "SECURITY") || str(r["referenceType"]) != "advisory" {
+				continue
+			}
+			findings = append(findings, Finding{
+				ID:       str(r["referenceLocator"]),
+				Package:  str(p["name"]),
+				Version:  str(p["versionInfo"]),
+				Severity: Unknown,
+			})
+		}
+	}
+
+	elements := make(map[string]map[string]interface{})
+	graph := list(report["@graph"])
+	for _, element := range graph {
+		e := object(element)
+		if id := str(e["spdxId"]); id != "" {
+			elements[id] = e
+		}
+	}
+	for _, element := range graph {
+		e := object(element)
+		if !strings.HasSuffix(str(e["type"]), "VulnAssessmentRelationship") {
+			continue
+		}
+
+		finding := Finding{ID: str(e["from"]), Severity: NormalizeSeverity(str(e["security_severity"]))}
+		if vuln, found := elements[finding.ID]; found && str(vuln["name"]) != "" {
+			finding.ID = str(vuln["name"])
+		}
+		if assessed, found := elements[str(e["security_assessedElement"])]; found {
+			finding.Package = str(assessed["name"])
+			finding.Version = str(assessed["software_packageVersion"])
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+func summarize(findings []Finding) Summary {
+	summary := Summary{Findings: make([]Finding, 0, len(findings))}
+	seen := make(map[string]bool)
+	for _, finding := range findings {
+		key := finding.ID + "\x00" + finding.Package + "\x00" + finding.Version
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		summary.Findings = append(summary.Findings, finding)
+
+		switch finding.Severity {
+		case Critical:
+			summary.Critical++
+		case High:
+			summary.High++
+		case Medium:
+			summary.Medium++
+		case Low:
+			summary.Low++
+		default:
+			summary.Unknown++
+		}
+	}
+	summary.Total = len(summary.Findings)
+
+	sort.SliceStable(summary.Findings, func(i, j int) bool {
+		return severityRanks[summary.Findings[i].Severity] > severityRanks[summary.Findings[j].Severity]
+	})
+
+	return summary
+}
+
+func list(value interface{}) []interface{} {
+	values, _ := value.([]interface{})
+	return values
+}
+
+func object(value interface{}) map[string]interface{} {
+	values, _ := value.(map[string]interface{})
+	return values
+}
+
+func str(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,213 @@
+package vulnreport_test
+
+import (
+	"testing"
+
+	"github.com/motain/of-catalog/internal/utils/vulnreport"
+	"github.com/stretchr/testify/assert"
+)
+
+const trivyReport = `{
+  "SchemaVersion": 2,
+  "ArtifactName": "my-service:1.2.3",
+  "Results": [
+    {
+      "Target": "my-service:1.2.3 (alpine 3.19.1)",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2024-0001", "PkgName": "openssl", "InstalledVersion": "3.1.4", "FixedVersion": "3.1.5", "Severity": "CRITICAL"},
+        {"VulnerabilityID": "CVE-2024-0002", "PkgName": "busybox", "InstalledVersion": "1.36.1", "Severity": "LOW"}
+      ]
+    },
+    {
+      "Target": "app/go.mod",
+      "Vulnerabilities": [
+        {"VulnerabilityID": "CVE-2024-0003", "PkgName": "golang.org/x/net", "InstalledVersion": "0.17.0", "Severity": "HIGH"},
+        {"VulnerabilityID": "CVE-2024-0001", "PkgName": "openssl", "InstalledVersion": "3.1.4", "FixedVersion": "3.1.5", "Severity": "CRITICAL"}
+      ]
+    },
+    {"Target": "app/package-lock.json"}
+  ]
+}`
+
+const grypeReport = `{
+  "matches": [
+    {"vulnerability": {"id": "GHSA-aaaa", "severity": "Medium", "fix": {"versions": ["2.0.1"]}}, "artifact": {"name": "lodash", "version": "2.0.0"}},
+    {"vulnerability": {"id": "CVE-2024-0004", "severity": "Negligible"}, "artifact": {"name": "zlib", "version": "1.3"}}
+  ],
+  "descriptor": {"name": "grype"}
+}`
+
+const cycloneDXReport = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "components": [
+    {"bom-ref": "pkg:npm/lodash@4.17.20", "name": "lodash", "version": "4.17.20"}
+  ],
+  "vulnerabilities": [
+    {
+      "id": "CVE-2021-23337",
+      "ratings": [{"source": {"name": "NVD"}, "severity": "medium"}, {"source": {"name": "GHSA"}, "severity": "high"}],
+      "affects": [{"ref": "pkg:npm/lodash@4.17.20"}]
+    },
+    {"id": "CVE-2024-0005"}
+  ]
+}`
+
+const spdx2Report = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {
+      "name": "log4j-core",
+      "versionInfo": "2.14.1",
+      "externalRefs": [
+        {"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+        {"referenceCategory": "SECURITY", "referenceType": "advisory", "referenceLocator": "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"}
+      ]
+    }
+  ]
+}`
+
+const spdx3Report = `{
+  "@context": "https://spdx.org/rdf/3.0.1/spdx-context.jsonld",
+  "@graph": [
+    {"type": "software_Package", "spdxId": "urn:pkg-1", "name": "log4j-core", "software_packageVersion": "2.14.1"},
+    {"type": "security_Vulnerability", "spdxId": "urn:vuln-1", "name": "CVE-2021-44228"},
+    {"type": "security_CvssV3VulnAssessmentRelationship", "from": "urn:vuln-1", "to": ["urn:pkg-1"], "security_assessedElement": "urn:pkg-1", "security_severity": "critical"}
+  ]
+}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    vulnreport.Format
+		report    string
+		expected  vulnreport.Summary
+		expectErr bool
+	}{
+		{
+			name:   "trivy report with findings repeated across targets",
+			format: vulnreport.TrivyFormat,
+			report: trivyReport,
+			expected: vulnreport.Summary{
+				Critical: 1, High: 1, Low: 1, Total: 3,
+				Findings: []vulnreport.Finding{
+					{ID: "CVE-2024-0001", Package: "openssl", Version: "3.1.4", FixedVersion: "3.1.5", Severity: vulnreport.Critical},
+					{ID: "CVE-2024-0003", Package: "golang.org/x/net", Version: "0.17.0", Severity: vulnreport.High},
+					{ID: "CVE-2024-0002", Package: "busybox", Version: "1.36.1", Severity: vulnreport.Low},
+				},
+			},
+		},
+		{
+			name:   "grype report detected automatically",
+			report: grypeReport,
+			expected: vulnreport.Summary{
+				Medium: 1, Low: 1, Total: 2,
+				Findings: []vulnreport.Finding{
+					{ID: "GHSA-aaaa", Package: "lodash", Version: "2.0.0", FixedVersion: "2.0.1", Severity: vulnreport.Medium},
+					{ID: "CVE-2024-0004", Package: "zlib", Version: "1.3", Severity: vulnreport.Low},
+				},
+			},
+		},
+		{
+			name:   "cyclonedx report takes the highest rating",
+			format: vulnreport.AutoFormat,
+			report: cycloneDXReport,
+			expected: vulnreport.Summary{
+				High: 1, Unknown: 1, Total: 2,
+				Findings: []vulnreport.Finding{
+					{ID: "CVE-2021-23337", Package: "lodash", Version: "4.17.20", Severity: vulnreport.High},
+					{ID: "CVE-2024-0005", Severity: vulnreport.Unknown},
+				},
+			},
+		},
+		{
+			name:   "cyclonedx none rating is not low",
+			format: vulnreport.CycloneDXFormat,
+			report: `{"bomFormat": "CycloneDX", "vulnerabilities": [{"id": "CVE-2024-0006", "ratings": [{"severity": "none"}]}]}`,
+			expected: vulnreport.Summary{
+				Unknown: 1, Total: 1,
+				Findings: []vulnreport.Finding{{ID: "CVE-2024-0006", Severity: vulnreport.Unknown}},
+			},
+		},
+		{
+			name:   "spdx 2 advisories have an unknown severity",
+			report: spdx2Report,
+			expected: vulnreport.Summary{
+				Unknown: 1, Total: 1,
+				Findings: []vulnreport.Finding{
+					{ID: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228", Package: "log4j-core", Version: "2.14.1", Severity: vulnreport.Unknown},
+				},
+			},
+		},
+		{
+			name:   "spdx 3 vulnerability assessments",
+			report: spdx3Report,
+			expected: vulnreport.Summary{
+				Critical: 1, Total: 1,
+				Findings: []vulnreport.Finding{
+					{ID: "CVE-2021-44228", Package: "log4j-core", Version: "2.14.1", Severity: vulnreport.Critical},
+				},
+			},
+		},
+		{
+			name:     "report without vulnerabilities",
+			format:   vulnreport.TrivyFormat,
+			report:   `{"SchemaVersion": 2, "Results": []}`,
+			expected: vulnreport.Summary{Findings: []vulnreport.Finding{}},
+		},
+		{
+			name:      "undetectable format",
+			report:    `{"foo": "bar"}`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid json",
+			format:    vulnreport.TrivyFormat,
+			report:    `not json`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := vulnreport.Parse(tt.format, []byte(tt.report))
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, summary)
+		})
+	}
+}
+
+func TestSummary_AtLeast(t *testing.T) {
+	summary, err := vulnreport.Parse(vulnreport.TrivyFormat, []byte(trivyReport))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		severity  vulnreport.Severity
+		expected  int
+		expectErr bool
+	}{
+		{severity: "", expected: 3},
+		{severity: vulnreport.Critical, expected: 1},
+		{severity: "HIGH", expected: 2},
+		{severity: vulnreport.Low, expected: 3},
+		{severity: "severe", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.severity), func(t *testing.T) {
+			count, err := summary.AtLeast(tt.severity)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, count)
+		})
+	}
+}